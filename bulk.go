@@ -0,0 +1,122 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ElementErrors holds the validation errors of one element of a slice
+// validated with IsValidAll.
+type ElementErrors struct {
+	Index  int
+	Errors []ValidationError
+}
+
+// IsValidAll validates every element of a slice or array using
+// DefaultValidationMap. See Map.IsValidAll.
+func IsValidAll(slice interface{}, opts Options) ([]ElementErrors, error) {
+	return DefaultMap.IsValidAll(slice, opts)
+}
+
+// IsValidAll validates every element of a slice or array concurrently with
+// opts.Workers goroutines. FailFast and MaxErrors limit the errors of the
+// whole slice, keeping those of the lowest indexes, and lookups are resolved
+// with one call per resolver for all elements. The errors of the invalid
+// elements are returned ordered by index, so the slice is valid if none are
+// returned and the error is nil. If opts.Context is done before every element
+// was validated, the errors found so far are returned with the context error.
+func (vm *Map) IsValidAll(slice interface{}, opts Options) ([]ElementErrors, error) {
+	sliceValue := reflect.ValueOf(slice)
+	if kind := sliceValue.Kind(); kind != reflect.Slice && kind != reflect.Array {
+		return nil, errors.New("validation: IsValidAll requires a slice or array, got " + kind.String())
+	}
+
+	return validateAll(vm, sliceValue.Len(), opts, func(r *run, i int) bool {
+		return r.validateValue(sliceValue.Index(i), "", true)
+	})
+}
+
+// validateAll validates every index below length with validate, using a pool
+// of opts.Workers goroutines and a run per element, and collects the errors
+// by index. Once the errors found reach the limit of opts no further elements
+// are started; as elements are started in order, the errors of the lowest
+// indexes are known by then. The lookups of all elements are resolved
+// together at the end and reported after the other errors of their element.
+func validateAll(vm *Map, length int, opts Options, validate func(r *run, i int) bool) ([]ElementErrors, error) {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > length {
+		workers = length
+	}
+	limit := opts.MaxErrors
+	if opts.FailFast {
+		limit = 1
+	}
+
+	runs := make([]*run, length)
+	goOn := make([]bool, length)
+	var next, found int64 = -1, 0
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil && (limit <= 0 || atomic.LoadInt64(&found) < int64(limit)) {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= length {
+					return
+				}
+				r := newRun(vm, opts)
+				goOn[i] = validate(r, i)
+				runs[i] = r
+				atomic.AddInt64(&found, int64(len(r.errors)))
+			}
+		}()
+	}
+	wg.Wait()
+
+	batch := newRun(vm, Options{Context: ctx})
+	batch.elements = make([][]ValidationError, length)
+	for i, r := range runs {
+		if r == nil || !goOn[i] {
+			continue
+		}
+		for _, name := range r.resolverNames {
+			for _, l := range r.lookups[name] {
+				l.element = i
+				batch.queueLookup(name, l)
+			}
+		}
+	}
+	batch.resolve()
+
+	var elementErrors []ElementErrors
+	count := 0
+	for i, r := range runs {
+		if r == nil {
+			continue
+		}
+		errs := append(r.errors, batch.elements[i]...)
+		if limit > 0 && count+len(errs) > limit {
+			errs = errs[:limit-count]
+		}
+		if len(errs) > 0 {
+			count += len(errs)
+			elementErrors = append(elementErrors, ElementErrors{Index: i, Errors: errs})
+		}
+		if limit > 0 && count == limit {
+			break
+		}
+	}
+	return elementErrors, ctx.Err()
+}
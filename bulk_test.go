@@ -0,0 +1,111 @@
+package validation
+
+import (
+	"context"
+	"testing"
+)
+
+type bulkTestType struct {
+	Name string `validation:"min_length=2"`
+}
+
+func TestIsValidAll(t *testing.T) {
+	records := make([]bulkTestType, 1000)
+	for i := range records {
+		records[i].Name = "ok"
+	}
+	records[3].Name = ""
+	records[998].Name = "x"
+
+	errs, err := IsValidAll(records, Options{Workers: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 2 || errs[0].Index != 3 || errs[1].Index != 998 {
+		t.Fatal("Expected errors for elements 3 and 998 in order", errs)
+	}
+	if len(errs[0].Errors) != 1 || errs[0].Errors[0].Key != "Name" {
+		t.Fatal("Unexpected element errors", errs[0].Errors)
+	}
+}
+
+func TestIsValidAllPointers(t *testing.T) {
+	records := []*bulkTestType{{Name: "ok"}, {Name: ""}}
+
+	errs, err := IsValidAll(records, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 || errs[0].Index != 1 {
+		t.Fatal("Expected an error for element 1", errs)
+	}
+}
+
+func TestIsValidAllEmpty(t *testing.T) {
+	errs, err := IsValidAll([]bulkTestType{}, Options{})
+	if err != nil || len(errs) != 0 {
+		t.Fatal("Empty slice should be valid", errs, err)
+	}
+}
+
+func TestIsValidAllNotSlice(t *testing.T) {
+	if _, err := IsValidAll(bulkTestType{}, Options{}); err == nil {
+		t.Fatal("Expected an error for a non slice")
+	}
+}
+
+func TestIsValidAllCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errs, err := IsValidAll(make([]bulkTestType, 100), Options{Context: ctx})
+	if err != context.Canceled {
+		t.Fatal("Expected the context error", err)
+	}
+	if len(errs) != 0 {
+		t.Fatal("No element should have been validated", errs)
+	}
+}
+
+func TestIsValidAllLimits(t *testing.T) {
+	records := make([]bulkTestType, 10)
+
+	errs, err := IsValidAll(records, Options{MaxErrors: 1, Workers: 4})
+	if err != nil || len(errs) != 1 || errs[0].Index != 0 || len(errs[0].Errors) != 1 {
+		t.Fatal("Expected a single error for element 0", errs, err)
+	}
+
+	errs, err = IsValidAll(records, Options{FailFast: true, Workers: 4})
+	if err != nil || len(errs) != 1 || errs[0].Index != 0 || len(errs[0].Errors) != 1 {
+		t.Fatal("Expected a single error for element 0", errs, err)
+	}
+
+	errs, err = IsValidAll(records, Options{MaxErrors: 3, Workers: 4})
+	if err != nil || len(errs) != 3 || errs[2].Index != 2 {
+		t.Fatal("Expected errors for elements 0 to 2", errs, err)
+	}
+}
+
+type bulkTestOrder struct {
+	SKU string `validation:"lookup=sku"`
+}
+
+func TestIsValidAllLookups(t *testing.T) {
+	skus := &countingResolver{Resolver: NewMemoryResolver("A1", "B2")}
+	vm := NewMap(&DefaultMap)
+	vm.AddResolver("sku", skus)
+
+	orders := []bulkTestOrder{{SKU: "A1"}, {SKU: "C3"}, {SKU: "B2"}, {SKU: "D4"}}
+	errs, err := vm.IsValidAll(orders, Options{Workers: 2})
+	if err != nil || len(errs) != 2 || errs[0].Index != 1 || errs[1].Index != 3 || errs[1].Errors[0].Key != "SKU" {
+		t.Fatal("Expected lookup errors for elements 1 and 3", errs, err)
+	}
+	if skus.calls != 1 || skus.keys != 4 {
+		t.Fatalf("Expected one call with 4 keys, got %d calls with %d keys", skus.calls, skus.keys)
+	}
+
+	errs, err = vm.IsValidAll(orders, Options{MaxErrors: 1})
+	if err != nil || len(errs) != 1 || errs[0].Index != 1 {
+		t.Fatal("Expected a single lookup error for element 1", errs, err)
+	}
+}
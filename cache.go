@@ -0,0 +1,161 @@
+package validation
+
+import (
+	"container/list"
+	"reflect"
+	"sync"
+)
+
+// typeCache bounds the number of types cached by a Map. Types are evicted in
+// the order they were cached.
+type typeCache struct {
+	mu       sync.Mutex
+	max      int
+	order    *list.List
+	elements map[reflect.Type]*list.Element
+}
+
+// compileCall is a compilation of a type other goroutines can wait for.
+type compileCall struct {
+	done     chan struct{}
+	compiled *compiledType
+	err      error
+}
+
+// SetMaxCachedTypes limits the number of types whose validations vm caches
+// to max, evicting the types cached first once more are used. Evicted types
+// are compiled again on their next use. A max of zero or less, the default,
+// means no limit. This keeps the cache bounded for maps validating
+// dynamically created types, e.g. from reflect.StructOf.
+func (vm *Map) SetMaxCachedTypes(max int) {
+	vm.cache.mu.Lock()
+	defer vm.cache.mu.Unlock()
+	vm.cache.max = max
+	vm.evictLocked()
+}
+
+// load returns the validations of objectType, compiling and caching them if
+// they are not cached yet. Concurrent loads of the same type share a single
+// compilation.
+func (vm *Map) load(objectType reflect.Type) (*compiledType, error) {
+	if compiled := vm.get(objectType); compiled != nil {
+		return compiled, nil
+	}
+
+	call := &compileCall{done: make(chan struct{})}
+	if running, loaded := vm.compiling.LoadOrStore(objectType, call); loaded {
+		running := running.(*compileCall)
+		<-running.done
+		if running.err == nil && !vm.fresh(running.compiled) {
+			return vm.load(objectType)
+		}
+		return running.compiled, running.err
+	}
+	defer func() {
+		vm.compiling.Delete(objectType)
+		close(call.done)
+	}()
+
+	// Another compilation may have finished between get and LoadOrStore.
+	if call.compiled = vm.get(objectType); call.compiled != nil {
+		return call.compiled, nil
+	}
+	call.compiled, call.err = vm.compile(objectType)
+	if call.err == nil {
+		vm.set(objectType, call.compiled)
+	}
+	return call.compiled, call.err
+}
+
+func (vm *Map) get(k reflect.Type) *compiledType {
+	v, ok := vm.validator.Load(k)
+	if !ok {
+		return nil
+	}
+	compiled := v.(*compiledType)
+	if compiled == nil || !vm.fresh(compiled) {
+		return nil
+	}
+	return compiled
+}
+func (vm *Map) set(k reflect.Type, v *compiledType) {
+	vm.cache.mu.Lock()
+	defer vm.cache.mu.Unlock()
+	vm.validator.Store(k, v)
+	if vm.cache.elements == nil {
+		vm.cache.order = list.New()
+		vm.cache.elements = map[reflect.Type]*list.Element{}
+	}
+	if _, ok := vm.cache.elements[k]; !ok {
+		vm.cache.elements[k] = vm.cache.order.PushBack(k)
+	}
+	vm.evictLocked()
+}
+
+// evictLocked drops the types cached first until no more than the maximum
+// number of types are cached. vm.cache.mu must be held.
+func (vm *Map) evictLocked() {
+	for vm.cache.max > 0 && len(vm.cache.elements) > vm.cache.max {
+		vm.forgetLocked(vm.cache.order.Front().Value.(reflect.Type))
+	}
+}
+
+// Reset drops the cached validations of every type, so they are built again
+// on next use.
+func (vm *Map) Reset() {
+	vm.validator.Range(func(key, value interface{}) bool {
+		vm.Forget(key.(reflect.Type))
+		return true
+	})
+}
+
+// Forget drops the cached validations of objectType, so they are built again
+// on next use.
+func (vm *Map) Forget(objectType reflect.Type) {
+	vm.cache.mu.Lock()
+	defer vm.cache.mu.Unlock()
+	vm.forgetLocked(objectType)
+}
+
+// forgetLocked drops objectType from the cache. vm.cache.mu must be held.
+func (vm *Map) forgetLocked(objectType reflect.Type) {
+	if v, ok := vm.validator.LoadAndDelete(objectType); ok && v.(*compiledType) != nil {
+		v.(*compiledType).forgotten.Store(true)
+	}
+	if element, ok := vm.cache.elements[objectType]; ok {
+		vm.cache.order.Remove(element)
+		delete(vm.cache.elements, objectType)
+	}
+}
+
+// fresh reports whether compiled was built with the validations and rules
+// currently registered on vm and its parents.
+func (vm *Map) fresh(compiled *compiledType) bool {
+	if compiled.forgotten.Load() {
+		return false
+	}
+	version := vm.versions()
+	if compiled.version.Load() == version {
+		return true
+	}
+	if vm.typeRules(compiled.objectType) != compiled.overrides {
+		return false
+	}
+	for name, reg := range compiled.uses {
+		if vm.registration(name) != reg {
+			return false
+		}
+	}
+	compiled.version.Store(version)
+	return true
+}
+
+// versions returns the sum of the versions of vm and its parents, which
+// changes whenever a validation is registered on any of them.
+func (vm *Map) versions() uint64 {
+	var version uint64
+	for m := vm; m != nil; m = m.parent {
+		version += m.version.Load()
+	}
+	return version
+}
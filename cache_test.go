@@ -0,0 +1,75 @@
+package validation
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCompileOnce(t *testing.T) {
+	vm := NewMap(nil)
+	var builds int64
+	vm.AddValidation("min", func(options string, kind reflect.Kind) (Interface, error) {
+		atomic.AddInt64(&builds, 1)
+		time.Sleep(10 * time.Millisecond)
+		return newMinValueValidation(options, kind)
+	})
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if ok, errs := vm.IsValid(kindsTestItem{Price: 0}); ok || len(errs) != 1 {
+				t.Error("Expected a Price error", errs)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if builds != 1 {
+		t.Fatalf("Expected the type to be compiled once, got %d builds", builds)
+	}
+}
+
+func TestMaxCachedTypes(t *testing.T) {
+	vm := NewMap(&DefaultMap)
+	vm.SetMaxCachedTypes(2)
+
+	var types []reflect.Type
+	for i := 0; i < 4; i++ {
+		typ := reflect.StructOf([]reflect.StructField{{
+			Name: "Value",
+			Type: reflect.TypeOf(0),
+			Tag:  reflect.StructTag(`validation:"min=` + string(rune('1'+i)) + `"`),
+		}})
+		types = append(types, typ)
+		if ok, _ := vm.IsValid(reflect.New(typ).Interface()); ok {
+			t.Fatal("Expected a zero Value to be invalid")
+		}
+	}
+
+	for i, typ := range types {
+		if cached := vm.get(typ) != nil; cached != (i >= 2) {
+			t.Fatalf("Type %d: expected only the last 2 types to be cached", i)
+		}
+	}
+
+	vm.Forget(types[3])
+	if err := vm.Compile(types[0]); err != nil {
+		t.Fatal(err)
+	}
+	if vm.get(types[2]) == nil || vm.get(types[0]) == nil {
+		t.Fatal("Expected forgotten types not to count towards the limit")
+	}
+
+	vm.SetMaxCachedTypes(1)
+	if vm.get(types[2]) != nil || vm.get(types[0]) == nil {
+		t.Fatal("Expected lowering the limit to evict the types cached first")
+	}
+}
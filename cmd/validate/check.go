@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	validation "github.com/BakedSoftware/go-validation"
+)
+
+// RecordErrors holds the errors of an invalid record. Record is the number
+// of the record in its file, counting the header of CSV files, and Line the
+// line it starts on.
+type RecordErrors struct {
+	File   string  `json:"file"`
+	Record int     `json:"record"`
+	Line   int     `json:"line"`
+	Errors []Error `json:"errors"`
+}
+
+// Error is a validation error of a record.
+type Error struct {
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+func (r RecordErrors) String() string {
+	var b strings.Builder
+	for i, err := range r.Errors {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "%s:%d: %s: %s", r.File, r.Line, err.Key, err.Message)
+	}
+	return b.String()
+}
+
+// Checker validates the records of NDJSON and CSV files.
+type Checker struct {
+	schema *validation.Schema
+	kinds  map[string]string
+}
+
+// NewChecker creates a Checker validating records with fields. Fields may
+// not have lookup rules, as validate has no resolvers for them.
+func NewChecker(fields []validation.SchemaField) (*Checker, error) {
+	for _, field := range fields {
+		if field.Rules == "" {
+			continue
+		}
+		rules, err := validation.ParseTag(field.Rules)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", field.Name, err)
+		}
+		for _, rule := range rules {
+			if rule.Name == "lookup" {
+				return nil, fmt.Errorf("field %s: lookup rules are not supported, as there are no resolvers to check them", field.Name)
+			}
+		}
+	}
+	schema, err := validation.NewSchema(fields...)
+	if err != nil {
+		return nil, err
+	}
+	c := &Checker{schema: schema, kinds: map[string]string{}}
+	for _, field := range fields {
+		c.kinds[field.Name] = field.Kind
+	}
+	return c, nil
+}
+
+// CheckNDJSON validates every line of r, read from the file name, as a JSON
+// object and calls report for the invalid ones. Blank lines are skipped.
+func (c *Checker) CheckNDJSON(name string, r io.Reader, report func(RecordErrors)) error {
+	reader := bufio.NewReader(r)
+	record := 0
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if len(bytes.TrimSpace(data)) > 0 {
+			record++
+			if errs := c.checkJSON(data); len(errs) > 0 {
+				report(RecordErrors{File: name, Record: record, Line: line, Errors: errs})
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// checkJSON validates the JSON document data.
+func (c *Checker) checkJSON(data []byte) []Error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document interface{}
+	err := decoder.Decode(&document)
+	if err == nil && decoder.More() {
+		err = errors.New("unexpected data after the object")
+	}
+	if err != nil {
+		return []Error{{Key: "object", Message: "is not valid JSON: " + err.Error()}}
+	}
+	_, errs := c.schema.Validate(document)
+	return convertErrors(errs)
+}
+
+// CheckCSV validates every record of the CSV file r, read from the file
+// name, and calls report for the invalid ones. The first record is the
+// header naming the fields of the columns. Cells are converted to the kinds
+// of their fields; empty cells count as missing.
+func (c *Checker) CheckCSV(name string, r io.Reader, report func(RecordErrors)) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for row := 2; ; row++ {
+		cells, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			report(RecordErrors{File: name, Record: row, Line: parseErr.StartLine, Errors: []Error{{Key: "object", Message: parseErr.Err.Error()}}})
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		record := map[string]interface{}{}
+		for i, cell := range cells {
+			if i < len(header) && cell != "" {
+				record[header[i]] = c.cellValue(header[i], cell)
+			}
+		}
+		if _, errs := c.schema.Validate(record); len(errs) > 0 {
+			line, _ := reader.FieldPos(0)
+			report(RecordErrors{File: name, Record: row, Line: line, Errors: convertErrors(errs)})
+		}
+	}
+}
+
+// cellValue converts cell to the kind of the field column, leaving it a
+// string if it cannot be converted so the Schema reports its type.
+func (c *Checker) cellValue(column, cell string) interface{} {
+	switch kind := c.kinds[column]; kind {
+	case "", "string":
+		return cell
+	case "bool":
+		if b, err := strconv.ParseBool(cell); err == nil {
+			return b
+		}
+		return cell
+	default:
+		if _, err := strconv.ParseFloat(cell, 64); err == nil {
+			return json.Number(cell)
+		}
+		return cell
+	}
+}
+
+func convertErrors(errs []validation.ValidationError) []Error {
+	converted := make([]Error, len(errs))
+	for i, err := range errs {
+		converted[i] = Error{Key: err.Key, Message: err.Message}
+	}
+	return converted
+}
+
+// ParseSchema parses a schema in the format of validation.ParseSchema.
+func ParseSchema(data []byte) ([]validation.SchemaField, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var fields []validation.SchemaField
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("invalid schema: %v", err)
+	}
+	return fields, nil
+}
+
+// ParseRules returns the fields of typeName in the rule file data, in the
+// format of validation.LoadRules, in the order of their names. typeName may
+// be empty if the file holds a single type. Fields with min or max rules are
+// numbers, of kind int64 if their limits are integers and float64 otherwise,
+// and fields with other rules strings. Fields without rules are left out, so
+// their values may be of any type.
+func ParseRules(data []byte, typeName string) ([]validation.SchemaField, error) {
+	var types map[string]map[string]string
+	if err := json.Unmarshal(data, &types); err != nil {
+		return nil, fmt.Errorf("invalid rule file: %v", err)
+	}
+	if typeName == "" {
+		if len(types) != 1 {
+			return nil, errors.New("the rule file holds more than one type; select one with -type")
+		}
+		for name := range types {
+			typeName = name
+		}
+	}
+	rules, ok := types[typeName]
+	if !ok {
+		return nil, fmt.Errorf("the rule file has no type %s", typeName)
+	}
+
+	fields := make([]validation.SchemaField, 0, len(rules))
+	for name, tag := range rules {
+		kind, err := ruleKind(tag)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %v", typeName, name, err)
+		}
+		if kind == "" {
+			continue
+		}
+		fields = append(fields, validation.SchemaField{Name: name, Kind: kind, Rules: tag})
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+	return fields, nil
+}
+
+// ruleKind returns the kind of a field with the rules of tag, or "" if tag
+// has no rules.
+func ruleKind(tag string) (string, error) {
+	if tag == "" {
+		return "", nil
+	}
+	rules, err := validation.ParseTag(tag)
+	if err != nil {
+		return "", err
+	}
+	kind := "string"
+	for _, rule := range rules {
+		if rule.Name != "min" && rule.Name != "max" {
+			continue
+		}
+		if _, err := strconv.ParseInt(rule.Options, 10, 64); err != nil {
+			return "float64", nil
+		}
+		kind = "int64"
+	}
+	return kind, nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	validation "github.com/BakedSoftware/go-validation"
+)
+
+const checkSchema = `[
+	{"name": "email", "kind": "string", "rules": "format=email", "required": true},
+	{"name": "age", "kind": "uint8", "rules": "min=18"},
+	{"name": "vip", "kind": "bool"}
+]`
+
+func checker(t *testing.T) *Checker {
+	fields, err := ParseSchema([]byte(checkSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewChecker(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCheckNDJSON(t *testing.T) {
+	input := `{"email": "bob@example.com", "age": 30}
+
+{"email": "bob", "age": 300}
+{"email": "al@example.com", "age": 17, "vip": true}
+{"email": "al@example.com"
+[1]`
+	var reported []RecordErrors
+	err := checker(t).CheckNDJSON("in.ndjson", strings.NewReader(input), func(errs RecordErrors) {
+		reported = append(reported, errs)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []RecordErrors{
+		{File: "in.ndjson", Record: 2, Line: 3, Errors: []Error{
+			{Key: "age", Message: "must be of type uint8"},
+			{Key: "email", Message: "does not match email format"},
+		}},
+		{File: "in.ndjson", Record: 3, Line: 4, Errors: []Error{{Key: "age", Message: "must be greater than or equal to 18"}}},
+		{File: "in.ndjson", Record: 4, Line: 5, Errors: []Error{{Key: "object", Message: "is not valid JSON: unexpected EOF"}}},
+		{File: "in.ndjson", Record: 5, Line: 6, Errors: []Error{{Key: "object", Message: "must be of type object"}}},
+	}
+	if !reflect.DeepEqual(reported, expected) {
+		t.Fatalf("Expected %v, got %v", expected, reported)
+	}
+	if reported[0].String() != "in.ndjson:3: age: must be of type uint8\nin.ndjson:3: email: does not match email format" {
+		t.Fatal("Unexpected text", reported[0].String())
+	}
+}
+
+func TestCheckCSV(t *testing.T) {
+	input := "email,age,vip,notes\n" +
+		"bob@example.com,30,true,\"multi\nline\"\n" +
+		",x,maybe\n" +
+		"al@example.com,17,false,a,b\n" +
+		"al@example.com,\"1\"8\n"
+	var reported []RecordErrors
+	err := checker(t).CheckCSV("in.csv", strings.NewReader(input), func(errs RecordErrors) {
+		reported = append(reported, errs)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []RecordErrors{
+		{File: "in.csv", Record: 3, Line: 4, Errors: []Error{
+			{Key: "vip", Message: "must be of type bool"},
+			{Key: "age", Message: "must be of type uint8"},
+			{Key: "email", Message: "is required"},
+		}},
+		{File: "in.csv", Record: 4, Line: 5, Errors: []Error{{Key: "age", Message: "must be greater than or equal to 18"}}},
+		{File: "in.csv", Record: 5, Line: 6, Errors: []Error{{Key: "object", Message: `extraneous or missing " in quoted-field`}}},
+	}
+	if !reflect.DeepEqual(reported, expected) {
+		t.Fatalf("Expected %v, got %v", expected, reported)
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	data := []byte(`{"Customer": {"Name": "min_length=1", "Age": "min=18 max=120", "Score": "max=9.5", "Notes": ""}, "Order": {}}`)
+	fields, err := ParseRules(data, "Customer")
+	expected := []validation.SchemaField{
+		{Name: "Age", Kind: "int64", Rules: "min=18 max=120"},
+		{Name: "Name", Kind: "string", Rules: "min_length=1"},
+		{Name: "Score", Kind: "float64", Rules: "max=9.5"},
+	}
+	if err != nil || !reflect.DeepEqual(fields, expected) {
+		t.Fatalf("Expected %v, got %v (%v)", expected, fields, err)
+	}
+
+	for _, typeName := range []string{"", "Invoice"} {
+		if _, err := ParseRules(data, typeName); err == nil {
+			t.Errorf("%q: expected an error", typeName)
+		}
+	}
+	if fields, err := ParseRules([]byte(`{"Order": {"ID": "min_length=1"}}`), ""); err != nil || len(fields) != 1 {
+		t.Fatal("Expected the single type to be used", fields, err)
+	}
+	if _, err := ParseRules([]byte(`{"Order": {"ID": "min_length"}}`), ""); err == nil {
+		t.Fatal("Expected an error for a malformed rule")
+	}
+}
+
+func TestNewCheckerLookup(t *testing.T) {
+	fields, err := ParseRules([]byte(`{"Order": {"SKU": "lookup=sku"}}`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewChecker(fields); err == nil || !strings.Contains(err.Error(), "lookup rules are not supported") {
+		t.Fatal("Expected an error for a lookup rule", err)
+	}
+}
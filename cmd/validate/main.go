@@ -0,0 +1,130 @@
+// Command validate checks NDJSON and CSV files against a schema, so records
+// can be validated without writing Go.
+//
+// Usage:
+//
+//	validate -schema=schema.json [-format=ndjson|csv] [-output=text|json] [files]
+//	validate -rules=rules.json [-type=name] [-format=ndjson|csv] [-output=text|json] [files]
+//
+// The schema describes the fields of the records as validation.ParseSchema
+// expects it. A rule file, as loaded by validation.LoadRules, may be given
+// instead, with -type selecting the type whose fields the records hold if it
+// lists more than one.
+//
+// Every line of an NDJSON file holds a record; CSV files start with a header
+// naming the fields of the columns. The format is taken from the extension of
+// the file unless -format is set; without files the standard input is read as
+// NDJSON. The errors of every invalid record are printed, as lines of
+// file:line: key: message or as one JSON object per record with -output=json.
+// The exit status is 1 if any record is invalid and 2 if the schema or a
+// file cannot be read.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	validation "github.com/BakedSoftware/go-validation"
+)
+
+var (
+	schemaFile = flag.String("schema", "", "schema file")
+	rulesFile  = flag.String("rules", "", "rule file, instead of a schema")
+	typeName   = flag.String("type", "", "type of the rule file the records hold")
+	format     = flag.String("format", "", "input format, ndjson or csv; defaults to the file extension")
+	output     = flag.String("output", "text", "output format, text or json")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: validate (-schema=file | -rules=file [-type=name]) [flags] [files]\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("validate: ")
+	flag.Usage = usage
+	flag.Parse()
+
+	if (*schemaFile == "") == (*rulesFile == "") || *output != "text" && *output != "json" ||
+		*format != "" && *format != "ndjson" && *format != "csv" {
+		usage()
+		os.Exit(2)
+	}
+
+	var fields []validation.SchemaField
+	var err error
+	if *schemaFile != "" {
+		var data []byte
+		if data, err = os.ReadFile(*schemaFile); err == nil {
+			fields, err = ParseSchema(data)
+		}
+	} else {
+		var data []byte
+		if data, err = os.ReadFile(*rulesFile); err == nil {
+			fields, err = ParseRules(data, *typeName)
+		}
+	}
+	if err != nil {
+		fatal(err)
+	}
+	checker, err := NewChecker(fields)
+	if err != nil {
+		fatal(err)
+	}
+
+	failed := false
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	report := func(errs RecordErrors) {
+		failed = true
+		if *output == "json" {
+			if err := encoder.Encode(errs); err != nil {
+				fatal(err)
+			}
+			return
+		}
+		fmt.Println(errs)
+	}
+
+	if flag.NArg() == 0 {
+		if err := check(checker, "<stdin>", os.Stdin, report); err != nil {
+			fatal(err)
+		}
+	}
+	for _, name := range flag.Args() {
+		file, err := os.Open(name)
+		if err != nil {
+			fatal(err)
+		}
+		err = check(checker, name, file, report)
+		file.Close()
+		if err != nil {
+			fatal(err)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// fatal reports err, a failure other than an invalid record, and exits.
+func fatal(err error) {
+	log.Print(err)
+	os.Exit(2)
+}
+
+// check validates the records of r, read from the file name, in the format
+// of -format or else the format the extension of name suggests.
+func check(checker *Checker, name string, r io.Reader, report func(RecordErrors)) error {
+	if *format == "csv" || *format == "" && strings.EqualFold(filepath.Ext(name), ".csv") {
+		return checker.CheckCSV(name, r, report)
+	}
+	return checker.CheckNDJSON(name, r, report)
+}
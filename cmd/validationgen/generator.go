@@ -0,0 +1,448 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	validation "github.com/BakedSoftware/go-validation"
+)
+
+// kinds maps the predeclared types supported by the generator to the
+// category of values they hold.
+var kinds = map[string]string{
+	"int":     "int",
+	"int8":    "int",
+	"int16":   "int",
+	"int32":   "int",
+	"int64":   "int",
+	"rune":    "int",
+	"uint":    "uint",
+	"uint8":   "uint",
+	"uint16":  "uint",
+	"uint32":  "uint",
+	"uint64":  "uint",
+	"byte":    "uint",
+	"float32": "float",
+	"float64": "float",
+	"string":  "string",
+}
+
+// bitSizes maps the sized numeric types to their size in bits. Options of
+// min and max are parsed with the size of the field, like the runtime
+// validations do.
+var bitSizes = map[string]int{
+	"int8":    8,
+	"int16":   16,
+	"int32":   32,
+	"int64":   64,
+	"rune":    32,
+	"uint8":   8,
+	"uint16":  16,
+	"uint32":  32,
+	"uint64":  64,
+	"byte":    8,
+	"float32": 32,
+	"float64": 64,
+}
+
+// reflectKinds maps the supported types to their reflect.Kind, so the rules
+// can be checked with the runtime builders before generating code for them.
+var reflectKinds = map[string]reflect.Kind{
+	"int":     reflect.Int,
+	"int8":    reflect.Int8,
+	"int16":   reflect.Int16,
+	"int32":   reflect.Int32,
+	"int64":   reflect.Int64,
+	"rune":    reflect.Int32,
+	"uint":    reflect.Uint,
+	"uint8":   reflect.Uint8,
+	"uint16":  reflect.Uint16,
+	"uint32":  reflect.Uint32,
+	"uint64":  reflect.Uint64,
+	"byte":    reflect.Uint8,
+	"float32": reflect.Float32,
+	"float64": reflect.Float64,
+	"string":  reflect.String,
+}
+
+// structType is a struct declaration.
+type structType struct {
+	name   string
+	fields []field
+}
+
+// field is a field of a struct. Fields are kept in declaration order.
+type field struct {
+	name     string
+	typ      string
+	tag      string
+	embedded bool
+	ptr      bool
+	pos      token.Position
+}
+
+// visibleField is a field accessible from a struct, either declared by it or
+// promoted from an embedded struct of the same package.
+type visibleField struct {
+	field
+	selector string   // expression of the field, e.g. v.Base.ID
+	guards   []string // nil checks of the embedded pointers on the way
+	depth    int
+}
+
+// Generator emits Validate methods for the tagged structs of a package.
+type Generator struct {
+	fset     *token.FileSet
+	pkg      string
+	structs  []structType
+	buf      bytes.Buffer
+	patterns []string
+}
+
+// NewGenerator creates a Generator.
+func NewGenerator() *Generator {
+	return &Generator{fset: token.NewFileSet()}
+}
+
+// ParseFile parses the Go source src, read from filename, and records its
+// structs with validation tags.
+func (g *Generator) ParseFile(filename string, src interface{}) error {
+	file, err := parser.ParseFile(g.fset, filename, src, 0)
+	if err != nil {
+		return err
+	}
+	if g.pkg == "" {
+		g.pkg = file.Name.Name
+	} else if g.pkg != file.Name.Name {
+		return fmt.Errorf("%s: package %s, expected %s", filename, file.Name.Name, g.pkg)
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			st, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			s, err := g.parseStruct(typeSpec.Name.Name, st)
+			if err != nil {
+				return err
+			}
+			g.structs = append(g.structs, s)
+		}
+	}
+	return nil
+}
+
+func (g *Generator) parseStruct(name string, st *ast.StructType) (structType, error) {
+	s := structType{name: name}
+	for _, f := range st.Fields.List {
+		var tag string
+		if f.Tag != nil {
+			rawTag, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return s, err
+			}
+			tag = reflect.StructTag(rawTag).Get("validation")
+		}
+		pos := g.fset.Position(f.Pos())
+
+		if len(f.Names) == 0 {
+			if len(tag) > 0 {
+				return s, fmt.Errorf("%s: embedded field of %s has a validation tag", pos, name)
+			}
+			typ, ptr := f.Type, false
+			if star, ok := typ.(*ast.StarExpr); ok {
+				typ, ptr = star.X, true
+			}
+			// Embedded types of other packages cannot be resolved and
+			// are skipped.
+			if ident, ok := typ.(*ast.Ident); ok {
+				s.fields = append(s.fields, field{name: ident.Name, typ: ident.Name, embedded: true, ptr: ptr, pos: pos})
+			}
+			continue
+		}
+
+		typ := exprString(f.Type)
+		if len(tag) > 0 && kinds[typ] == "" {
+			return s, fmt.Errorf("%s: unsupported type %s for validated field %s.%s", pos, typ, name, f.Names[0].Name)
+		}
+		for _, n := range f.Names {
+			s.fields = append(s.fields, field{name: n.Name, typ: typ, tag: tag, pos: pos})
+		}
+	}
+	return s, nil
+}
+
+// visibleFields returns the fields accessible from s in the order of
+// reflect.VisibleFields: embedded fields are followed by the fields promoted
+// from them and fields hidden by a shallower one are left out.
+func (g *Generator) visibleFields(s structType) []visibleField {
+	var all []visibleField
+	var walk func(s structType, selector string, guards []string, depth int, seen map[string]bool)
+	walk = func(s structType, selector string, guards []string, depth int, seen map[string]bool) {
+		for _, f := range s.fields {
+			vf := visibleField{field: f, selector: selector + "." + f.name, guards: guards, depth: depth}
+			all = append(all, vf)
+			if !f.embedded || seen[f.typ] {
+				continue
+			}
+			embedded, ok := g.lookup(f.typ)
+			if !ok {
+				continue
+			}
+			embeddedGuards := guards
+			if f.ptr {
+				embeddedGuards = append(append([]string(nil), guards...), vf.selector+" != nil")
+			}
+			seen[f.typ] = true
+			walk(embedded, vf.selector, embeddedGuards, depth+1, seen)
+			delete(seen, f.typ)
+		}
+	}
+	walk(s, "v", nil, 0, map[string]bool{s.name: true})
+
+	minDepth := map[string]int{}
+	count := map[string]int{}
+	for _, f := range all {
+		if d, ok := minDepth[f.name]; !ok || f.depth < d {
+			minDepth[f.name] = f.depth
+			count[f.name] = 0
+		}
+		if f.depth == minDepth[f.name] {
+			count[f.name]++
+		}
+	}
+	var visible []visibleField
+	for _, f := range all {
+		if f.depth == minDepth[f.name] && count[f.name] == 1 {
+			visible = append(visible, f)
+		}
+	}
+	return visible
+}
+
+// tagged reports whether s has a visible field with validation tags.
+func (g *Generator) tagged(s structType) bool {
+	for _, f := range g.visibleFields(s) {
+		if len(f.tag) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Generate returns the formatted source of the Validate methods of the
+// structs named by types, or of every tagged struct if types is empty.
+func (g *Generator) Generate(types []string) ([]byte, error) {
+	var structs []structType
+	if len(types) == 0 {
+		for _, s := range g.structs {
+			if g.tagged(s) {
+				structs = append(structs, s)
+			}
+		}
+	} else {
+		for _, name := range types {
+			s, ok := g.lookup(name)
+			if !ok || !g.tagged(s) {
+				return nil, fmt.Errorf("no struct with validation tags named %s", name)
+			}
+			structs = append(structs, s)
+		}
+	}
+
+	var body bytes.Buffer
+	g.buf.Reset()
+	g.patterns = nil
+	for _, s := range structs {
+		if err := g.generateStruct(s); err != nil {
+			return nil, err
+		}
+	}
+	body.Write(g.buf.Bytes())
+
+	g.buf.Reset()
+	g.printf("// Code generated by validationgen; DO NOT EDIT.\n\n")
+	g.printf("package %s\n\n", g.pkg)
+	g.printf("import (\n")
+	if len(g.patterns) > 0 {
+		g.printf("\t\"regexp\"\n\n")
+	}
+	g.printf("\tvalidation \"github.com/BakedSoftware/go-validation\"\n")
+	g.printf(")\n\n")
+	if len(g.patterns) > 0 {
+		g.printf("var (\n")
+		for i, pattern := range g.patterns {
+			g.printf("\tvalidationgenPattern%d = regexp.MustCompile(%s)\n", i, pattern)
+		}
+		g.printf(")\n\n")
+	}
+	g.buf.Write(body.Bytes())
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v", err)
+	}
+	return src, nil
+}
+
+func (g *Generator) lookup(name string) (structType, bool) {
+	for _, s := range g.structs {
+		if s.name == name {
+			return s, true
+		}
+	}
+	return structType{}, false
+}
+
+func (g *Generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// generateStruct emits the Validate method of s. Fields are checked from last
+// to first, like Map.IsValid, so both report errors in the same order.
+func (g *Generator) generateStruct(s structType) error {
+	g.printf("// Validate checks %s against its validation tags.\n", s.name)
+	g.printf("func (v %s) Validate() validation.ValidationErrors {\n", s.name)
+	g.printf("\tvar errs validation.ValidationErrors\n")
+	fields := g.visibleFields(s)
+	for i := len(fields) - 1; i >= 0; i-- {
+		f := fields[i]
+		if len(f.tag) == 0 {
+			continue
+		}
+		rules, err := validation.ParseTag(f.tag)
+		if err != nil {
+			return fmt.Errorf("%s: %s.%s: %v", f.pos, s.name, f.name, err)
+		}
+		if _, err := validation.DefaultMap.BuildRules(rules, reflectKinds[f.typ]); err != nil {
+			return fmt.Errorf("%s: %s.%s: %v", f.pos, s.name, f.name, err)
+		}
+		for _, rule := range rules {
+			if err := g.generateRule(f, rule); err != nil {
+				return fmt.Errorf("%s: %s.%s: %s: %v", f.pos, s.name, f.name, rule, err)
+			}
+		}
+	}
+	g.printf("\treturn errs\n")
+	g.printf("}\n\n")
+	return nil
+}
+
+func (g *Generator) generateRule(f visibleField, rule validation.Rule) error {
+	kind := kinds[f.typ]
+	switch rule.Name {
+	case "min":
+		return g.generateValue(f, kind, rule.Options, "<", "must be greater than or equal to ")
+	case "max":
+		return g.generateValue(f, kind, rule.Options, ">", "must be less than or equal to ")
+	case "min_length":
+		return g.generateLength(f, kind, rule.Options, "<", "must be at least ")
+	case "max_length":
+		return g.generateLength(f, kind, rule.Options, ">", "must be no more than ")
+	case "format":
+		return g.generateFormat(f, kind, rule.Options)
+	}
+	return fmt.Errorf("unsupported validation %s", rule.Name)
+}
+
+func (g *Generator) generateValue(f visibleField, kind, options, op, message string) error {
+	var cond, limit string
+	switch kind {
+	case "int":
+		value, err := strconv.ParseInt(options, 10, bitSizes[f.typ])
+		if err != nil {
+			return err
+		}
+		cond = fmt.Sprintf("int64(%s) %s %d", f.selector, op, value)
+		limit = strconv.FormatInt(value, 10)
+	case "uint":
+		value, err := strconv.ParseUint(options, 10, bitSizes[f.typ])
+		if err != nil {
+			return err
+		}
+		cond = fmt.Sprintf("uint64(%s) %s %d", f.selector, op, value)
+		limit = strconv.FormatUint(value, 10)
+	case "float":
+		value, err := strconv.ParseFloat(options, bitSizes[f.typ])
+		if err != nil {
+			return err
+		}
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			return fmt.Errorf("unsupported value %s", options)
+		}
+		cond = fmt.Sprintf("float64(%s) %s %s", f.selector, op, strconv.FormatFloat(value, 'g', -1, 64))
+		limit = strconv.FormatFloat(value, 'E', -1, 64)
+	default:
+		return fmt.Errorf("field is not of numeric type")
+	}
+	g.generateCheck(f, cond, message+limit)
+	return nil
+}
+
+func (g *Generator) generateLength(f visibleField, kind, options, op, message string) error {
+	if kind != "string" {
+		return fmt.Errorf("field is not of type string")
+	}
+	length, err := strconv.ParseInt(options, 10, 0)
+	if err != nil {
+		return err
+	}
+	g.generateCheck(f, fmt.Sprintf("len(%s) %s %d", f.selector, op, length), message+strconv.Itoa(int(length))+" characters")
+	return nil
+}
+
+// generateFormat mirrors the option parsing of the format validation.
+func (g *Generator) generateFormat(f visibleField, kind, options string) error {
+	if kind != "string" {
+		return fmt.Errorf("field is not of type string")
+	}
+	var pattern, patternName string
+	if strings.ToLower(options) == "email" {
+		pattern = "validation.EmailPattern"
+		patternName = "email"
+	} else if strings.Contains(options, "regexp:") {
+		patternStr := options[strings.Index(options, ":")+1:]
+		if _, err := regexp.Compile(patternStr); err != nil {
+			return err
+		}
+		pattern = strconv.Quote(patternStr)
+		patternName = "regexp"
+	} else {
+		return fmt.Errorf("has no pattern %s", options)
+	}
+
+	name := fmt.Sprintf("validationgenPattern%d", len(g.patterns))
+	g.patterns = append(g.patterns, pattern)
+	g.generateCheck(f, fmt.Sprintf("!%s.MatchString(%s)", name, f.selector), "does not match "+patternName+" format")
+	return nil
+}
+
+func (g *Generator) generateCheck(f visibleField, cond, message string) {
+	if len(f.guards) > 0 {
+		cond = strings.Join(f.guards, " && ") + " && " + cond
+	}
+	g.printf("\tif %s {\n", cond)
+	g.printf("\t\terrs = append(errs, validation.ValidationError{Key: %q, Message: %q})\n", f.name, message)
+	g.printf("\t}\n")
+}
+
+func exprString(expr ast.Expr) string {
+	var buf bytes.Buffer
+	format.Node(&buf, token.NewFileSet(), expr)
+	return buf.String()
+}
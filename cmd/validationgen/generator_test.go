@@ -0,0 +1,143 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const personSource = `package people
+
+type Person struct {
+	Name  string  ` + "`validation:\"min_length=1 max_length=5\"`" + `
+	Email string  ` + "`validation:\"format=email\"`" + `
+	Code  string  ` + "`validation:\"format=regexp:^[A-Z]+$\"`" + `
+	Age   uint8   ` + "`validation:\"min=18\"`" + `
+	Score float32 ` + "`validation:\"min=-1.5 max=10\"`" + `
+	Debt  int     ` + "`validation:\"max=-20\"`" + `
+	Notes string
+}
+
+type Empty struct {
+	Name string
+}
+`
+
+func TestGenerate(t *testing.T) {
+	g := NewGenerator()
+	if err := g.ParseFile("person.go", personSource); err != nil {
+		t.Fatal(err)
+	}
+	src, err := g.Generate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+
+	expected := []string{
+		"// Code generated by validationgen; DO NOT EDIT.",
+		"package people",
+		"validationgenPattern0 = regexp.MustCompile(\"^[A-Z]+$\")",
+		"validationgenPattern1 = regexp.MustCompile(validation.EmailPattern)",
+		"func (v Person) Validate() validation.ValidationErrors {",
+		"if int64(v.Debt) > -20 {",
+		"if float64(v.Score) < -1.5 {",
+		`Message: "must be greater than or equal to -1.5E+00"`,
+		"if uint64(v.Age) < 18 {",
+		"if !validationgenPattern1.MatchString(v.Email) {",
+		`Message: "does not match email format"`,
+		"if len(v.Name) < 1 {",
+		`Message: "must be no more than 5 characters"`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(code, e) {
+			t.Fatalf("Expected generated code to contain %q:\n%s", e, code)
+		}
+	}
+	if strings.Contains(code, "Empty") {
+		t.Fatal("Struct without validation tags should be skipped")
+	}
+	if strings.Index(code, "v.Debt") > strings.Index(code, "v.Name") {
+		t.Fatal("Fields should be validated from last to first")
+	}
+}
+
+func TestGenerateTypes(t *testing.T) {
+	g := NewGenerator()
+	if err := g.ParseFile("person.go", personSource); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Generate([]string{"Person"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Generate([]string{"Empty"}); err == nil {
+		t.Fatal("Expected an error for a struct without validation tags")
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	sources := map[string]string{
+		"unknown validation": "type T struct { A string `validation:\"lookup=sku\"` }",
+		"length on int":      "type T struct { A int `validation:\"min_length=1\"` }",
+		"min on string":      "type T struct { A string `validation:\"min=1\"` }",
+		"invalid regexp":     "type T struct { A string `validation:\"format=regexp:[\"` }",
+		"unknown format":     "type T struct { A string `validation:\"format=phone\"` }",
+		"invalid option":     "type T struct { A int `validation:\"min=abc\"` }",
+		"malformed tag":      "type T struct { A int `validation:\"min\"` }",
+		"out of range":       "type T struct { A uint8 `validation:\"min=300\"` }",
+		"conflicting limits": "type T struct { A int `validation:\"min=10 max=5\"` }",
+		"unsupported type":   "type T struct { A *string `validation:\"min_length=1\"` }",
+	}
+	for name, src := range sources {
+		g := NewGenerator()
+		err := g.ParseFile("t.go", "package p\n"+src)
+		if err == nil {
+			_, err = g.Generate(nil)
+		}
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+const embeddedSource = `package people
+
+type Base struct {
+	ID   uint   ` + "`validation:\"min=1\"`" + `
+	Name string ` + "`validation:\"min_length=2\"`" + `
+}
+
+type Audit struct {
+	Author string ` + "`validation:\"min_length=1\"`" + `
+}
+
+type Record struct {
+	Base
+	*Audit
+	Name string
+}
+`
+
+func TestGenerateEmbedded(t *testing.T) {
+	g := NewGenerator()
+	if err := g.ParseFile("record.go", embeddedSource); err != nil {
+		t.Fatal(err)
+	}
+	src, err := g.Generate([]string{"Record"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+
+	if !strings.Contains(code, "if v.Audit != nil && len(v.Audit.Author) < 1 {") {
+		t.Fatalf("Expected a nil guarded check of the promoted Author:\n%s", code)
+	}
+	if !strings.Contains(code, "if uint64(v.Base.ID) < 1 {") {
+		t.Fatalf("Expected a check of the promoted ID:\n%s", code)
+	}
+	if strings.Contains(code, "v.Base.Name") {
+		t.Fatalf("Base.Name is hidden by Record.Name and should not be checked:\n%s", code)
+	}
+	if strings.Index(code, "v.Audit.Author") > strings.Index(code, "v.Base.ID") {
+		t.Fatal("Promoted fields should be validated from last to first")
+	}
+}
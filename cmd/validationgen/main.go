@@ -0,0 +1,115 @@
+// Command validationgen generates reflection free Validate methods for structs
+// with validation tags. The generated methods implement the same checks as
+// the min, max, min_length, max_length and format validations of
+// github.com/BakedSoftware/go-validation and return the errors in the same
+// order as Map.IsValid. Fields promoted from embedded structs declared in
+// the same package are checked as well; embedded types of other packages
+// are skipped.
+//
+// It is meant to be run by go generate:
+//
+//	//go:generate validationgen -type=Person,Order
+//
+// Without arguments the Go files of the current directory are parsed, test
+// files excluded; otherwise the named files or directories are parsed.
+// Without -type a method is generated for every struct with validation tags.
+// The output is written to <file>_validation.go, where file is $GOFILE, or to
+// validation_gen.go when not run by go generate, unless -output is set.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	typeNames = flag.String("type", "", "comma separated list of struct names; defaults to every struct with validation tags")
+	output    = flag.String("output", "", "output file name")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: validationgen [flags] [directory | files...]\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("validationgen: ")
+	flag.Usage = usage
+	flag.Parse()
+
+	outputName := *output
+	if outputName == "" {
+		if gofile := os.Getenv("GOFILE"); gofile != "" {
+			outputName = strings.TrimSuffix(gofile, ".go") + "_validation.go"
+		} else {
+			outputName = "validation_gen.go"
+		}
+	}
+
+	args := flag.Args()
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	files, err := sourceFiles(args, outputName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	g := NewGenerator()
+	for _, file := range files {
+		if err := g.ParseFile(file, nil); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var types []string
+	if *typeNames != "" {
+		types = strings.Split(*typeNames, ",")
+	}
+	src, err := g.Generate(types)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(args) == 1 && isDir(args[0]) && !filepath.IsAbs(outputName) {
+		outputName = filepath.Join(args[0], outputName)
+	}
+	if err := ioutil.WriteFile(outputName, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// sourceFiles expands args into the Go files to parse, skipping test files
+// of directories and the output file.
+func sourceFiles(args []string, outputName string) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		if !isDir(arg) {
+			files = append(files, arg)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(arg, "*.go"))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			base := filepath.Base(match)
+			if strings.HasSuffix(base, "_test.go") || base == filepath.Base(outputName) {
+				continue
+			}
+			files = append(files, match)
+		}
+	}
+	return files, nil
+}
+
+func isDir(name string) bool {
+	info, err := os.Stat(name)
+	return err == nil && info.IsDir()
+}
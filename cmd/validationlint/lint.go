@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+
+	validation "github.com/BakedSoftware/go-validation"
+)
+
+// Diagnostic is a problem found in a validation tag.
+type Diagnostic struct {
+	Pos     token.Position
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s", d.Pos, d.Message)
+}
+
+// Linter checks the validation tags of type checked packages against the
+// validations registered in a validation.Map.
+type Linter struct {
+	// Map holds the known validations. validation.DefaultMap is used if nil.
+	Map *validation.Map
+
+	// Known lists additional validation names, registered at runtime by the
+	// linted program, which are accepted without further checks.
+	Known map[string]bool
+}
+
+// CheckPackage type checks files, which must form a single package, and
+// checks every tagged struct field in them. Type errors are ignored as far
+// as possible so that partially broken packages can still be linted.
+func (l *Linter) CheckPackage(fset *token.FileSet, files []*ast.File) []Diagnostic {
+	info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}}
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {},
+	}
+	if len(files) > 0 {
+		conf.Check(files[0].Name.Name, fset, files, info)
+	}
+
+	var diagnostics []Diagnostic
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			st, ok := n.(*ast.StructType)
+			if !ok {
+				return true
+			}
+			for _, field := range st.Fields.List {
+				diagnostics = append(diagnostics, l.checkField(fset, info, field)...)
+			}
+			return true
+		})
+	}
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i].Pos, diagnostics[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+	return diagnostics
+}
+
+func (l *Linter) checkField(fset *token.FileSet, info *types.Info, field *ast.Field) []Diagnostic {
+	if field.Tag == nil {
+		return nil
+	}
+	rawTag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return nil
+	}
+	tag, ok := reflect.StructTag(rawTag).Lookup("validation")
+	if !ok {
+		return nil
+	}
+
+	pos := fset.Position(field.Tag.Pos())
+	name := fieldName(field)
+	var diagnostics []Diagnostic
+	report := func(format string, args ...interface{}) {
+		diagnostics = append(diagnostics, Diagnostic{
+			Pos:     pos,
+			Message: name + ": " + fmt.Sprintf(format, args...),
+		})
+	}
+
+	rules, err := validation.ParseTag(tag)
+	if err != nil {
+		report("malformed validation tag %q: %v", tag, err)
+		return diagnostics
+	}
+
+	vm := l.Map
+	if vm == nil {
+		vm = &validation.DefaultMap
+	}
+	typ := info.TypeOf(field.Type)
+	kind := kindOf(typ)
+	var checked []validation.Rule
+	for _, rule := range rules {
+		if l.Known[rule.Name] {
+			continue
+		}
+		if !vm.HasValidation(rule.Name) {
+			report("unknown validation %s", rule.Name)
+			continue
+		}
+		if typ != nil {
+			// The built-in validations type switch on the predeclared
+			// types, so values of defined types fail them at runtime.
+			_, predeclared := types.Unalias(typ).(*types.Basic)
+			switch rule.Name {
+			case "min", "max":
+				if !isNumeric(kind) {
+					report("%s requires a numeric field, not %s", rule.Name, typ)
+					continue
+				}
+				if !predeclared {
+					report("%s requires a field of a predeclared numeric type, not %s", rule.Name, typ)
+					continue
+				}
+			case "min_length", "max_length", "format":
+				if kind != reflect.String || !predeclared {
+					report("%s requires a string field, not %s", rule.Name, typ)
+					continue
+				}
+			}
+		}
+		checked = append(checked, rule)
+	}
+
+	if len(diagnostics) == 0 && typ != nil {
+		if _, err := vm.BuildRules(checked, kind); err != nil {
+			report("%v", err)
+		}
+	}
+	return diagnostics
+}
+
+func fieldName(field *ast.Field) string {
+	if len(field.Names) > 0 {
+		return field.Names[0].Name
+	}
+	return types.ExprString(field.Type)
+}
+
+// kindOf returns the reflect.Kind of values of typ. Invalid is returned for
+// types that could not be determined.
+func kindOf(typ types.Type) reflect.Kind {
+	if typ == nil {
+		return reflect.Invalid
+	}
+	switch t := typ.Underlying().(type) {
+	case *types.Basic:
+		return basicKinds[t.Kind()]
+	case *types.Pointer:
+		return reflect.Ptr
+	case *types.Slice:
+		return reflect.Slice
+	case *types.Array:
+		return reflect.Array
+	case *types.Map:
+		return reflect.Map
+	case *types.Struct:
+		return reflect.Struct
+	case *types.Interface:
+		return reflect.Interface
+	case *types.Chan:
+		return reflect.Chan
+	case *types.Signature:
+		return reflect.Func
+	}
+	return reflect.Invalid
+}
+
+var basicKinds = map[types.BasicKind]reflect.Kind{
+	types.Bool:          reflect.Bool,
+	types.Int:           reflect.Int,
+	types.Int8:          reflect.Int8,
+	types.Int16:         reflect.Int16,
+	types.Int32:         reflect.Int32,
+	types.Int64:         reflect.Int64,
+	types.Uint:          reflect.Uint,
+	types.Uint8:         reflect.Uint8,
+	types.Uint16:        reflect.Uint16,
+	types.Uint32:        reflect.Uint32,
+	types.Uint64:        reflect.Uint64,
+	types.Uintptr:       reflect.Uintptr,
+	types.Float32:       reflect.Float32,
+	types.Float64:       reflect.Float64,
+	types.Complex64:     reflect.Complex64,
+	types.Complex128:    reflect.Complex128,
+	types.String:        reflect.String,
+	types.UnsafePointer: reflect.UnsafePointer,
+}
+
+func isNumeric(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+const lintSource = `package p
+
+import "time"
+
+type Age uint8
+
+type Name string
+
+type Alias = string
+
+type T struct {
+	Name     string        ` + "`validation:\"min_length=1 max_length=5\"`" + `
+	Age      Age           ` + "`validation:\"min=18 max=120\"`" + `
+	Timeout  time.Duration ` + "`validation:\"min=1\"`" + `
+	Unknown  string        ` + "`validation:\"phone=us\"`" + `
+	Custom   string        ` + "`validation:\"tenant=true\"`" + `
+	Broken   string        ` + "`validation:\"min_length\"`" + `
+	Options  int           ` + "`validation:\"min=abc\"`" + `
+	Length   int           ` + "`validation:\"min_length=3\"`" + `
+	Numeric  string        ` + "`validation:\"max=3\"`" + `
+	Pattern  string        ` + "`validation:\"format=regexp:[a-\"`" + `
+	Range    int           ` + "`validation:\"min=10 max=5\"`" + `
+	Lengths  string        ` + "`validation:\"min_length=10 max_length=5\"`" + `
+	Small    uint8         ` + "`validation:\"min=300\"`" + `
+	Untagged string
+	Named    Name          ` + "`validation:\"min_length=2\"`" + `
+	Aliased  Alias         ` + "`validation:\"max_length=2\"`" + `
+}
+`
+
+func lint(t *testing.T, src string) []Diagnostic {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := &Linter{Known: map[string]bool{"tenant": true}}
+	return l.CheckPackage(fset, []*ast.File{file})
+}
+
+func TestLint(t *testing.T) {
+	diagnostics := lint(t, lintSource)
+
+	expected := []struct {
+		line    int
+		message string
+	}{
+		{13, "Age: min requires a field of a predeclared numeric type, not p.Age"},
+		{13, "Age: max requires a field of a predeclared numeric type, not p.Age"},
+		{14, "Timeout: min requires a field of a predeclared numeric type, not time.Duration"},
+		{15, "Unknown: unknown validation phone"},
+		{17, "Broken: malformed validation tag"},
+		{18, "Options: min=abc strconv.ParseInt"},
+		{19, "Length: min_length requires a string field, not int"},
+		{20, "Numeric: max requires a numeric field, not string"},
+		{21, "Pattern: format=regexp:[a- regexp:"},
+		{22, "Range: min=10 conflicts with max=5"},
+		{23, "Lengths: min_length=10 conflicts with max_length=5"},
+		{24, "Small: min=300 strconv.ParseUint: parsing \"300\": value out of range"},
+		{26, "Named: min_length requires a string field, not p.Name"},
+	}
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("Expected %d diagnostics, got %v", len(expected), diagnostics)
+	}
+	for i, d := range diagnostics {
+		if !strings.Contains(d.Message, expected[i].message) {
+			t.Errorf("Diagnostic %d: expected %q in %q", i, expected[i].message, d.Message)
+		}
+		if d.Pos.Filename != "p.go" || d.Pos.Line != expected[i].line {
+			t.Errorf("Diagnostic %d: unexpected position %s", i, d.Pos)
+		}
+	}
+}
+
+func TestLintClean(t *testing.T) {
+	src := "package p\n\ntype T struct {\n\tName string `validation:\"format=email\"`\n\tAge int `json:\"age\"`\n}\n"
+	if diagnostics := lint(t, src); len(diagnostics) != 0 {
+		t.Fatal("Expected no diagnostics", diagnostics)
+	}
+}
@@ -0,0 +1,128 @@
+// Command validationlint reports problems in validation tags before they
+// surface at runtime: unknown validation names, malformed tags and options,
+// length and format rules on non string fields, min and max on non numeric
+// fields, built-in rules on fields of defined types such as time.Duration,
+// invalid regular expressions and minimums greater than maximums.
+//
+// Usage:
+//
+//	validationlint [-known=name,...] [-test] [packages]
+//
+// Packages are directories; a trailing /... also checks every directory
+// below. Without arguments the current directory is checked. Validations
+// registered at runtime by the program can be accepted with -known. The exit
+// status is 1 if any problem was found.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var (
+	known = flag.String("known", "", "comma separated list of additional validation names")
+	tests = flag.Bool("test", false, "also check test files")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: validationlint [flags] [packages]\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("validationlint: ")
+	flag.Usage = usage
+	flag.Parse()
+
+	l := &Linter{Known: map[string]bool{}}
+	for _, name := range strings.Split(*known, ",") {
+		if name != "" {
+			l.Known[name] = true
+		}
+	}
+
+	args := flag.Args()
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	dirs, err := expandDirs(args)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	failed := false
+	for _, dir := range dirs {
+		fset := token.NewFileSet()
+		for _, files := range parseDir(fset, dir) {
+			for _, d := range l.CheckPackage(fset, files) {
+				fmt.Println(d)
+				failed = true
+			}
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// expandDirs resolves the package patterns in args to directories.
+func expandDirs(args []string) ([]string, error) {
+	var dirs []string
+	for _, arg := range args {
+		if !strings.HasSuffix(arg, "/...") {
+			dirs = append(dirs, arg)
+			continue
+		}
+		root := strings.TrimSuffix(arg, "/...")
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				return nil
+			}
+			if name := info.Name(); path != root && (name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			dirs = append(dirs, path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dirs, nil
+}
+
+// parseDir parses the Go files of dir grouped by package name. Files that do
+// not parse are reported and skipped.
+func parseDir(fset *token.FileSet, dir string) map[string][]*ast.File {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	sort.Strings(matches)
+
+	packages := map[string][]*ast.File{}
+	for _, match := range matches {
+		if !*tests && strings.HasSuffix(match, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, match, nil, 0)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		packages[file.Name.Name] = append(packages[file.Name.Name], file)
+	}
+	return packages
+}
@@ -0,0 +1,296 @@
+package validation
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// CompileError describes why the validation tags of a field could not be
+// compiled.
+type CompileError struct {
+	Type  reflect.Type
+	Field string
+	Err   error
+}
+
+func (e *CompileError) Error() string {
+	return "validation: " + e.Type.String() + "." + e.Field + ": " + e.Err.Error()
+}
+
+// Compile builds and caches the validations of objectType using
+// DefaultValidationMap. See Map.Compile.
+func Compile(objectType reflect.Type) error {
+	return DefaultMap.Compile(objectType)
+}
+
+// Compile builds and caches the validations of objectType, which IsValid
+// otherwise does on first use. Unlike IsValid, which exits the program on
+// invalid validation tags, it returns a *CompileError, so types can be
+// checked e.g. at startup or in tests.
+func (vm *Map) Compile(objectType reflect.Type) error {
+	_, err := vm.load(objectType)
+	return err
+}
+
+// compiledType holds the validations compiled for a struct type.
+type compiledType struct {
+	objectType reflect.Type
+	rules      []compiledRule
+
+	// nested holds the exported fields which may hold structs to validate.
+	nested []nestedField
+
+	// uses holds the registrations the rules were built with by name and
+	// version the sum of the map versions they were last found current at.
+	// forgotten is set once the type was dropped from the cache.
+	uses      map[string]*registration
+	version   atomic.Uint64
+	forgotten atomic.Bool
+
+	// overrides holds the rules of SetRules the type was compiled with.
+	overrides *typeRules
+}
+
+// nestedField is a field holding a struct, a pointer to a struct or a slice,
+// array or interface which may hold structs.
+type nestedField struct {
+	name  string
+	index []int
+}
+
+// compiledRule is a validation compiled for a field of a struct type.
+type compiledRule struct {
+	Interface
+	rule Rule
+
+	// index is the index sequence of the field for FieldByIndex and field
+	// the position of the field in the visible fields of the type.
+	index []int
+	field int
+}
+
+// compile builds the validations of objectType from its validation tags and
+// the rules set for it with SetRules.
+func (vm *Map) compile(objectType reflect.Type) (*compiledType, error) {
+	version := vm.versions()
+	compiled, err := vm.compileWith(objectType, vm.typeRules(objectType))
+	if err != nil {
+		return nil, err
+	}
+	compiled.version.Store(version)
+	return compiled, nil
+}
+
+// compileWith builds the validations of objectType from its validation tags,
+// with the rules of the fields in overrides replacing their tags.
+// Fields are visited from last to first. Fields promoted from embedded
+// structs are validated with their promoted name, as if they were declared
+// on objectType. Unexported fields are read without exposing them to other
+// packages, which is only possible for fields of basic kinds.
+func (vm *Map) compileWith(objectType reflect.Type, overrides *typeRules) (*compiledType, error) {
+	compiled := &compiledType{
+		objectType: objectType,
+		uses:       map[string]*registration{},
+		overrides:  overrides,
+	}
+	fields := reflect.VisibleFields(objectType)
+	for i := len(fields) - 1; i >= 0; i-- {
+		field := fields[i]
+		if !field.Anonymous && field.IsExported() && holdsStructs(field.Type) {
+			compiled.nested = append(compiled.nested, nestedField{name: field.Name, index: field.Index})
+		}
+		fieldRules, overridden := overrides.field(field)
+		validationTag := field.Tag.Get("validation")
+		if len(fieldRules) == 0 && (overridden || len(validationTag) == 0) {
+			continue
+		}
+		if !field.IsExported() && !isBasicKind(field.Type.Kind()) {
+			return nil, &CompileError{
+				Type:  objectType,
+				Field: field.Name,
+				Err:   &ValidationError{Key: field.Type.String(), Message: "cannot be validated in an unexported field"},
+			}
+		}
+		var err error
+		if !overridden {
+			fieldRules, err = ParseTag(validationTag)
+		}
+		if err == nil {
+			for _, rule := range fieldRules {
+				if _, ok := compiled.uses[rule.Name]; !ok {
+					compiled.uses[rule.Name] = vm.registration(rule.Name)
+				}
+			}
+			var validations []Interface
+			validations, err = vm.BuildRules(fieldRules, field.Type.Kind())
+			for j, validation := range validations {
+				validation.SetFieldName(field.Name)
+				validation.SetFieldIndex(field.Index[len(field.Index)-1])
+				compiled.rules = append(compiled.rules, compiledRule{
+					Interface: validation,
+					rule:      fieldRules[j],
+					index:     field.Index,
+					field:     i,
+				})
+			}
+		}
+		if err != nil {
+			return nil, &CompileError{Type: objectType, Field: field.Name, Err: err}
+		}
+	}
+	return compiled, nil
+}
+
+// holdsStructs reports whether values of typ may hold structs to validate.
+func holdsStructs(typ reflect.Type) bool {
+	for {
+		switch typ.Kind() {
+		case reflect.Struct, reflect.Interface:
+			return true
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			typ = typ.Elem()
+		default:
+			return false
+		}
+	}
+}
+
+// fieldParent returns the struct holding the field at index in objectValue.
+// For fields promoted from embedded structs this is the embedded struct. ok
+// is false if the field is promoted through a nil pointer.
+func fieldParent(objectValue reflect.Value, index []int) (parent reflect.Value, ok bool) {
+	parent, err := objectValue.FieldByIndexErr(index[:len(index)-1])
+	if err != nil {
+		return parent, false
+	}
+	if parent.Kind() == reflect.Ptr {
+		if parent.IsNil() {
+			return parent, false
+		}
+		parent = parent.Elem()
+	}
+	return parent, true
+}
+
+// fieldValue returns the value of field as an interface{}. Unexported fields
+// of basic kinds, which reflect does not allow to be returned directly, are
+// copied into a new value of the same type.
+func fieldValue(field reflect.Value) interface{} {
+	if field.CanInterface() {
+		return field.Interface()
+	}
+	value := reflect.New(field.Type()).Elem()
+	switch field.Kind() {
+	case reflect.Bool:
+		value.SetBool(field.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value.SetInt(field.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		value.SetUint(field.Uint())
+	case reflect.Float32, reflect.Float64:
+		value.SetFloat(field.Float())
+	case reflect.Complex64, reflect.Complex128:
+		value.SetComplex(field.Complex())
+	case reflect.String:
+		value.SetString(field.String())
+	}
+	return value.Interface()
+}
+
+// isBasicKind reports whether fieldValue can read unexported fields of kind.
+func isBasicKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return false
+}
+
+// BuildRules creates the validations for the rules of a single field of the
+// given kind. Besides the errors of the builders, it reports rules that
+// contradict each other, such as min=10 max=5.
+func (vm *Map) BuildRules(rules []Rule, kind reflect.Kind) ([]Interface, error) {
+	validations := make([]Interface, 0, len(rules))
+	lower := map[string]ruleLimit{}
+	upper := map[string]ruleLimit{}
+	for _, rule := range rules {
+		if !vm.HasValidation(rule.Name) {
+			return nil, unknownValidationError(rule.Name)
+		}
+		validation, err := vm.Build(rule, kind)
+		if err != nil {
+			return nil, &ValidationError{Key: rule.String(), Message: err.Error()}
+		}
+		if l, ok := validation.(limiter); ok {
+			what, value, isLower := l.limit()
+			if isLower {
+				lower[what] = ruleLimit{rule, value}
+			} else {
+				upper[what] = ruleLimit{rule, value}
+			}
+		}
+		validations = append(validations, validation)
+	}
+
+	for what, min := range lower {
+		if max, ok := upper[what]; ok && exceeds(min.value, max.value) {
+			return nil, &ValidationError{
+				Key:     min.rule.String(),
+				Message: "conflicts with " + max.rule.String(),
+			}
+		}
+	}
+	return validations, nil
+}
+
+// limiter is implemented by validations enforcing a lower or an upper limit,
+// so that contradicting limits on the same field can be detected.
+type limiter interface {
+	// limit returns what is limited, e.g. "value" or "length", the limit,
+	// which is an int, int64, uint64 or float64, and whether it is a lower
+	// limit.
+	limit() (what string, value interface{}, lower bool)
+}
+
+type ruleLimit struct {
+	rule  Rule
+	value interface{}
+}
+
+// exceeds reports whether the limit lower is greater than upper.
+func exceeds(lower, upper interface{}) bool {
+	switch l := lower.(type) {
+	case int:
+		u, ok := upper.(int)
+		return ok && l > u
+	case int64:
+		u, ok := upper.(int64)
+		return ok && l > u
+	case uint64:
+		u, ok := upper.(uint64)
+		return ok && l > u
+	case float64:
+		u, ok := upper.(float64)
+		return ok && l > u
+	}
+	return false
+}
+
+// bitSize returns the size in bits of values of kind, or 0 for int and uint,
+// which strconv treats as their platform size.
+func bitSize(kind reflect.Kind) int {
+	switch kind {
+	case reflect.Int8, reflect.Uint8:
+		return 8
+	case reflect.Int16, reflect.Uint16:
+		return 16
+	case reflect.Int32, reflect.Uint32, reflect.Float32:
+		return 32
+	case reflect.Int64, reflect.Uint64, reflect.Float64:
+		return 64
+	}
+	return 0
+}
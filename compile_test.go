@@ -0,0 +1,154 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	type valid struct {
+		Age   uint8   `validation:"min=18 max=255"`
+		Delta int8    `validation:"min=-128 max=127"`
+		Ratio float32 `validation:"min=0 max=1"`
+		Name  string  `validation:"min_length=1 max_length=1"`
+	}
+	if err := Compile(reflect.TypeOf(valid{})); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompileOutOfRange(t *testing.T) {
+	tests := []struct {
+		object  interface{}
+		message string
+	}{
+		{struct {
+			Age uint8 `validation:"min=300"`
+		}{}, "Age: min=300 strconv.ParseUint: parsing \"300\": value out of range"},
+		{struct {
+			Delta int8 `validation:"max=128"`
+		}{}, "Delta: max=128 strconv.ParseInt"},
+		{struct {
+			Count uint16 `validation:"min=-1"`
+		}{}, "Count: min=-1 strconv.ParseUint"},
+		{struct {
+			Ratio float32 `validation:"max=1e39"`
+		}{}, "Ratio: max=1e39 strconv.ParseFloat"},
+	}
+	for _, test := range tests {
+		err := Compile(reflect.TypeOf(test.object))
+		if err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Errorf("Expected error containing %q, got %v", test.message, err)
+		}
+	}
+}
+
+func TestCompileConflicts(t *testing.T) {
+	tests := []struct {
+		object  interface{}
+		message string
+	}{
+		{struct {
+			Quantity int `validation:"min=10 max=5"`
+		}{}, "Quantity: min=10 conflicts with max=5"},
+		{struct {
+			Quantity uint `validation:"max=5 min=10"`
+		}{}, "Quantity: min=10 conflicts with max=5"},
+		{struct {
+			Total float64 `validation:"min=0.5 max=0.25"`
+		}{}, "Total: min=0.5 conflicts with max=0.25"},
+		{struct {
+			Title string `validation:"min_length=5 max_length=3"`
+		}{}, "Title: min_length=5 conflicts with max_length=3"},
+	}
+	for _, test := range tests {
+		err := Compile(reflect.TypeOf(test.object))
+		if err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Errorf("Expected error containing %q, got %v", test.message, err)
+		}
+		if _, ok := err.(*CompileError); !ok {
+			t.Errorf("Expected a *CompileError, got %T", err)
+		}
+	}
+}
+
+func TestCompileUnknownValidation(t *testing.T) {
+	type unknown struct {
+		Phone string `validation:"phone=us"`
+	}
+	err := Compile(reflect.TypeOf(unknown{}))
+	if err == nil || !strings.HasSuffix(err.Error(), "Phone: phone is not a known validation") {
+		t.Fatal("Expected unknown validation error", err)
+	}
+}
+
+type embeddedBase struct {
+	ID   uint   `validation:"min=1"`
+	Name string `validation:"min_length=2"`
+}
+
+type embeddedAudit struct {
+	Author string `validation:"min_length=1"`
+}
+
+func TestEmbeddedFields(t *testing.T) {
+	type record struct {
+		embeddedBase
+		*embeddedAudit
+		Name  string `validation:"max_length=3"`
+		Total int    `validation:"min=0"`
+	}
+
+	obj := record{Name: "abcd", Total: -1}
+	ok, errs := IsValid(obj)
+	if ok || len(errs) != 3 {
+		t.Fatal("Expected errors for Total, Name and the promoted ID", errs)
+	}
+	keys := []string{"Total", "Name", "ID"}
+	for i, key := range keys {
+		if errs[i].Key != key {
+			t.Fatalf("Error %d: expected key %s, got %v", i, key, errs)
+		}
+	}
+	if errs[1].Message != "must be no more than 3 characters" {
+		t.Fatal("Shadowed embeddedBase.Name should not be validated", errs)
+	}
+
+	obj = record{embeddedBase: embeddedBase{ID: 1}, embeddedAudit: &embeddedAudit{}}
+	ok, errs = IsValid(obj)
+	if ok || len(errs) != 1 || errs[0].Key != "Author" {
+		t.Fatal("Expected the promoted Author to be validated", errs)
+	}
+}
+
+func TestUnexportedFields(t *testing.T) {
+	type secret struct {
+		code  string `validation:"min_length=4"`
+		count uint8  `validation:"max=3"`
+	}
+
+	ok, errs := IsValid(secret{code: "abc", count: 4})
+	if ok || len(errs) != 2 {
+		t.Fatal("Expected unexported fields to be validated", errs)
+	}
+	if errs[0].Key != "count" || errs[1].Key != "code" {
+		t.Fatal("Unexpected error keys", errs)
+	}
+
+	ok, errs = IsValid(secret{code: "abcd", count: 3})
+	if !ok {
+		t.Fatal("Expected valid unexported fields", errs)
+	}
+}
+
+func TestUnexportedFieldsUnsupported(t *testing.T) {
+	type secret struct {
+		codes []string `validation:"lookup=codes"`
+	}
+
+	err := Compile(reflect.TypeOf(secret{}))
+	if err == nil || !strings.HasSuffix(err.Error(), "codes: []string cannot be validated in an unexported field") {
+		t.Fatal("Expected unexported slice to be rejected", err)
+	}
+}
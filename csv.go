@@ -0,0 +1,391 @@
+package validation
+
+import (
+	"encoding"
+	"encoding/csv"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// CSVError describes an invalid cell of a CSV record.
+type CSVError struct {
+	// Row is the number of the record in the file, the header being row 1,
+	// and Line the line the cell starts on.
+	Row, Line int
+
+	// Column is the header of the column of the field, which is the name
+	// of the field if the file has no such column, or empty for errors
+	// about the record itself.
+	Column string
+
+	// Code is the name of the failing rule, e.g. min or lookup, "type" for
+	// cells which cannot be converted to the type of their field and
+	// "context" if the context of the Options was done.
+	Code    string
+	Message string
+}
+
+func (e *CSVError) Error() string {
+	msg := "validation: row " + strconv.Itoa(e.Row)
+	if e.Column != "" {
+		msg += ", column " + e.Column
+	}
+	return msg + ": " + e.Message
+}
+
+// CSVReader reads the records of a CSV file into structs and validates them.
+type CSVReader struct {
+	vm         *Map
+	reader     *csv.Reader
+	objectType reflect.Type
+	opts       Options
+	header     []string
+	row        int
+
+	// columns holds the field of every column, nil for columns without one,
+	// and names the column name of every field by field index.
+	columns []*reflect.StructField
+	names   map[string]string
+	cells   map[string]int
+
+	// ruleColumns holds the column of every rule of compiled, the
+	// validations last used.
+	compiled    *compiledType
+	ruleColumns []string
+}
+
+// NewCSVReader reads the header of reader and returns a CSVReader for
+// objectType using DefaultValidationMap. See Map.NewCSVReader.
+func NewCSVReader(reader *csv.Reader, objectType reflect.Type, opts Options) (*CSVReader, error) {
+	return DefaultMap.NewCSVReader(reader, objectType, opts)
+}
+
+// NewCSVReader reads the header of reader and returns a CSVReader reading
+// its records into values of the struct type objectType, or the struct type
+// it points to, and validating them with the validations of vm and opts.
+//
+// Columns are matched to the exported fields of objectType, including those
+// promoted from embedded structs, by the name in their csv tag or else their
+// name, ignoring case if there is no exact match. Fields tagged csv:"-" are
+// ignored, as are columns without a field and, like encoding/json does,
+// fields promoted through embedded pointers to unexported structs. Fields
+// must be of a basic kind, implement encoding.TextUnmarshaler or point to
+// such a type. Only the rules of the fields of objectType are run, not those
+// of nested structs.
+func (vm *Map) NewCSVReader(reader *csv.Reader, objectType reflect.Type, opts Options) (*CSVReader, error) {
+	for objectType != nil && objectType.Kind() == reflect.Ptr {
+		objectType = objectType.Elem()
+	}
+	if objectType == nil || objectType.Kind() != reflect.Struct {
+		return nil, errors.New("validation: NewCSVReader requires a struct type")
+	}
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &CSVReader{
+		vm:         vm,
+		reader:     reader,
+		objectType: objectType,
+		opts:       opts,
+		header:     header,
+		row:        1,
+		columns:    make([]*reflect.StructField, len(header)),
+		names:      map[string]string{},
+		cells:      map[string]int{},
+	}
+	var fields []reflect.StructField
+	for _, field := range reflect.VisibleFields(objectType) {
+		name := field.Tag.Get("csv")
+		if field.Anonymous || !field.IsExported() || name == "-" || viaUnexportedPointer(objectType, field.Index) {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		field.Name = name
+		fields = append(fields, field)
+	}
+	for _, exact := range []bool{true, false} {
+		for i, column := range header {
+			if r.columns[i] != nil {
+				continue
+			}
+			for j := range fields {
+				field := &fields[j]
+				if _, taken := r.names[indexKey(field.Index)]; taken {
+					continue
+				}
+				if exact && column == field.Name || !exact && strings.EqualFold(column, field.Name) {
+					if !isCellType(field.Type) {
+						return nil, errors.New("validation: CSV column " + column + " cannot be read into a field of type " + field.Type.String())
+					}
+					r.columns[i] = field
+					r.names[indexKey(field.Index)] = column
+					r.cells[column] = i
+					break
+				}
+			}
+		}
+	}
+	for _, field := range fields {
+		if _, ok := r.names[indexKey(field.Index)]; !ok {
+			r.names[indexKey(field.Index)] = field.Name
+		}
+	}
+	return r, nil
+}
+
+// Header returns the header of the file.
+func (r *CSVReader) Header() []string {
+	return r.header
+}
+
+// Read reads the next record into a new value of the struct type, returned
+// as a pointer, and validates it. Cells which cannot be converted are
+// reported first, in the order of the columns, followed by the errors of the
+// rules of the other fields in the order IsValid reports them. At the end of
+// the file, Read returns io.EOF; errors of reader, such as a
+// *csv.ParseError, are returned as they are. The lookups of every record are
+// resolved when it is read, with one call per resolver and record.
+func (r *CSVReader) Read() (interface{}, []CSVError, error) {
+	if r.opts.Context != nil {
+		if err := r.opts.Context.Err(); err != nil {
+			return nil, nil, err
+		}
+	}
+	compiled, err := r.vm.load(r.objectType)
+	if err != nil {
+		return nil, nil, err
+	}
+	record, err := r.reader.Read()
+	if err != io.EOF {
+		// Records which cannot be parsed still take up a row.
+		r.row++
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if compiled != r.compiled {
+		r.setRules(compiled)
+	}
+
+	object := reflect.New(r.objectType)
+	run := newRun(r.vm, r.opts)
+	var codes []string
+	invalid := map[string]bool{}
+	goOn := true
+	for i, field := range r.columns {
+		if field == nil || i >= len(record) {
+			continue
+		}
+		if !setCell(settableField(object.Elem(), field.Index), record[i]) {
+			invalid[r.header[i]] = true
+			codes = append(codes, "type")
+			if goOn = run.fail(ValidationError{Key: r.header[i], Message: "must be of type " + field.Type.String()}); !goOn {
+				break
+			}
+		}
+	}
+	if goOn {
+		goOn = r.validate(run, object.Elem(), invalid, &codes)
+	}
+	if goOn {
+		run.resolve()
+	}
+
+	errs := make([]CSVError, len(run.errors))
+	for i, err := range run.errors {
+		errs[i] = CSVError{Row: r.row, Column: err.Key, Code: "lookup", Message: err.Message}
+		if i < len(codes) {
+			errs[i].Code = codes[i]
+		} else if err.Key == "context" && i == len(run.errors)-1 {
+			errs[i].Column, errs[i].Code = "", "context"
+		}
+		cell, ok := r.cells[errs[i].Column]
+		if !ok || cell >= len(record) {
+			cell = 0
+		}
+		errs[i].Line, _ = r.reader.FieldPos(cell)
+	}
+	return object.Interface(), errs, nil
+}
+
+// setRules finds the columns of the rules of compiled.
+func (r *CSVReader) setRules(compiled *compiledType) {
+	r.compiled = compiled
+	r.ruleColumns = make([]string, len(compiled.rules))
+	for i, rule := range compiled.rules {
+		column, ok := r.names[indexKey(rule.index)]
+		if !ok {
+			column = r.objectType.FieldByIndex(rule.index).Name
+		}
+		r.ruleColumns[i] = column
+	}
+}
+
+// validate runs the rules of the type against object, recording the name
+// of the failing rule of every error in codes. Rules of the columns in
+// invalid are skipped. It reports whether validation should go on.
+func (r *CSVReader) validate(run *run, object reflect.Value, invalid map[string]bool, codes *[]string) bool {
+	failedField := -1
+	for i, rule := range r.compiled.rules {
+		if run.cancelled() {
+			return false
+		}
+		column := r.ruleColumns[i]
+		if rule.field == failedField || invalid[column] {
+			continue
+		}
+		parent, ok := fieldParent(object, rule.index)
+		if !ok {
+			continue
+		}
+		field := parent.Field(rule.index[len(rule.index)-1])
+		if lv, ok := rule.Interface.(*lookupValidation); ok {
+			run.addLookup(lv.resolver, column, fieldValue(field))
+			continue
+		}
+		value := fieldValue(field)
+		var err *ValidationError
+		if cv, ok := rule.Interface.(ContextValidator); ok {
+			err = cv.ValidateContext(run.ctx, value, parent)
+		} else {
+			err = rule.Validate(value, parent)
+		}
+		if err != nil {
+			err.Key = column
+			*codes = append(*codes, rule.rule.Name)
+			if !run.fail(*err) {
+				return false
+			}
+			if run.opts.Bail {
+				failedField = rule.field
+			}
+		}
+	}
+	return true
+}
+
+// ValidateCSV validates the records of the CSV file read from reader using
+// DefaultValidationMap. See Map.ValidateCSV.
+func ValidateCSV(reader io.Reader, objectType reflect.Type, opts Options) ([]CSVError, error) {
+	return DefaultMap.ValidateCSV(reader, objectType, opts)
+}
+
+// ValidateCSV validates every record of the CSV file read from reader like
+// a CSVReader and returns the errors of all records, resolving lookups row by
+// row. The errors found before a failure to read the file are returned with
+// the error.
+func (vm *Map) ValidateCSV(reader io.Reader, objectType reflect.Type, opts Options) ([]CSVError, error) {
+	r, err := vm.NewCSVReader(csv.NewReader(reader), objectType, opts)
+	if err != nil {
+		return nil, err
+	}
+	var all []CSVError
+	for {
+		_, errs, err := r.Read()
+		if err == io.EOF {
+			return all, nil
+		}
+		all = append(all, errs...)
+		if err != nil {
+			return all, err
+		}
+	}
+}
+
+// viaUnexportedPointer reports whether the field at index in the struct type
+// typ is promoted through an embedded pointer to an unexported struct, which
+// cannot be allocated from outside its package.
+func viaUnexportedPointer(typ reflect.Type, index []int) bool {
+	for _, i := range index[:len(index)-1] {
+		field := typ.Field(i)
+		if field.Type.Kind() == reflect.Ptr {
+			if !field.IsExported() {
+				return true
+			}
+			typ = field.Type.Elem()
+		} else {
+			typ = field.Type
+		}
+	}
+	return false
+}
+
+// isCellType reports whether values of typ can be read from a cell.
+func isCellType(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if reflect.PtrTo(typ).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch typ.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// setCell sets field to the value of cell and reports whether cell holds a
+// value of its type. Empty cells leave fields at their zero value.
+func setCell(field reflect.Value, cell string) bool {
+	if cell == "" {
+		return true
+	}
+	if field.Kind() == reflect.Ptr {
+		field.Set(reflect.New(field.Type().Elem()))
+		field = field.Elem()
+	}
+	if u, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(cell)) == nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(cell)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return false
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(cell, 10, bitSize(field.Kind()))
+		if err != nil {
+			return false
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(cell, 10, bitSize(field.Kind()))
+		if err != nil {
+			return false
+		}
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(cell, bitSize(field.Kind()))
+		if err != nil {
+			return false
+		}
+		field.SetFloat(f)
+	default:
+		return false
+	}
+	return true
+}
+
+// indexKey returns a map key for the field index sequence index.
+func indexKey(index []int) string {
+	key := make([]string, len(index))
+	for i, n := range index {
+		key[i] = strconv.Itoa(n)
+	}
+	return strings.Join(key, ".")
+}
@@ -0,0 +1,171 @@
+package validation
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type csvTestBase struct {
+	Email string `csv:"e-mail" validation:"format=email"`
+}
+
+type csvTestCustomer struct {
+	csvTestBase
+	Name   string `validation:"min_length=2 max_length=10"`
+	Age    uint8  `csv:"age" validation:"min=18"`
+	Plan   string `csv:"plan" validation:"lookup=plan"`
+	Score  *float64
+	Joined time.Time `csv:"joined"`
+	Notes  string    `csv:"-" validation:"max_length=2"`
+	Tags   []string  `csv:"tags"`
+}
+
+func csvTestMap() *Map {
+	vm := NewMap(&DefaultMap)
+	vm.AddResolver("plan", NewMemoryResolver("free", "pro"))
+	return vm
+}
+
+func TestCSVReader(t *testing.T) {
+	data := "NAME,e-mail,age,plan,score,joined,notes\n" +
+		"Bob,bob@example.com,30,pro,1.5,2026-10-19T12:00:00Z,\"multi\nline\"\n" +
+		"B,bob,300,gold,high,yesterday,x\n" +
+		"Alice,alice@example.com,17,free,,,\n"
+	r, err := csvTestMap().NewCSVReader(csv.NewReader(strings.NewReader(data)), reflect.TypeOf(&csvTestCustomer{}), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r.Header(), []string{"NAME", "e-mail", "age", "plan", "score", "joined", "notes"}) {
+		t.Fatal("Unexpected header", r.Header())
+	}
+
+	object, errs, err := r.Read()
+	customer, ok := object.(*csvTestCustomer)
+	if err != nil || len(errs) != 0 || !ok {
+		t.Fatal("Expected the first record to be valid", errs, err)
+	}
+	if customer.Name != "Bob" || customer.Email != "bob@example.com" || customer.Age != 30 || *customer.Score != 1.5 || customer.Joined.Year() != 2026 {
+		t.Fatal("Unexpected record", customer)
+	}
+
+	_, errs, err = r.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []CSVError{
+		{Row: 3, Line: 4, Column: "age", Code: "type", Message: "must be of type uint8"},
+		{Row: 3, Line: 4, Column: "score", Code: "type", Message: "must be of type *float64"},
+		{Row: 3, Line: 4, Column: "joined", Code: "type", Message: "must be of type time.Time"},
+		{Row: 3, Line: 4, Column: "NAME", Code: "min_length", Message: "must be at least 2 characters"},
+		{Row: 3, Line: 4, Column: "e-mail", Code: "format", Message: "does not match email format"},
+		{Row: 3, Line: 4, Column: "plan", Code: "lookup", Message: "is not a valid plan"},
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("Expected %v, got %v", expected, errs)
+	}
+
+	_, errs, err = r.Read()
+	expected = []CSVError{{Row: 4, Line: 5, Column: "age", Code: "min", Message: "must be greater than or equal to 18"}}
+	if err != nil || !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("Expected %v, got %v (%v)", expected, errs, err)
+	}
+	if errs[0].Error() != "validation: row 4, column age: must be greater than or equal to 18" {
+		t.Fatal("Unexpected message", errs[0].Error())
+	}
+
+	if _, _, err := r.Read(); err != io.EOF {
+		t.Fatal("Expected io.EOF, got", err)
+	}
+}
+
+func TestCSVReaderOptions(t *testing.T) {
+	data := "Name,e-mail,age\nB,bob,1\nBob,bob,1\n"
+	r, err := csvTestMap().NewCSVReader(csv.NewReader(strings.NewReader(data)), reflect.TypeOf(csvTestCustomer{}), Options{FailFast: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, errs, err := r.Read(); err != nil || len(errs) != 1 || errs[0].Column != "age" {
+		t.Fatal("Expected a single error", errs, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r, err = csvTestMap().NewCSVReader(csv.NewReader(strings.NewReader(data)), reflect.TypeOf(csvTestCustomer{}), Options{Context: ctx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, errs, err := r.Read(); err != nil || len(errs) != 4 {
+		t.Fatal("Expected four errors", errs, err)
+	}
+	cancel()
+	if _, _, err := r.Read(); err != context.Canceled {
+		t.Fatal("Expected the context error, got", err)
+	}
+}
+
+func TestCSVReaderParseError(t *testing.T) {
+	data := "name,age,e-mail,plan\nBob,30,bob@example.com,pro\nB\"ob,40,x,pro\nAl,17,al@example.com,pro\n"
+	r, err := csvTestMap().NewCSVReader(csv.NewReader(strings.NewReader(data)), reflect.TypeOf(csvTestCustomer{}), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, errs, err := r.Read(); err != nil || len(errs) != 0 {
+		t.Fatal("Expected the first record to be valid", errs, err)
+	}
+	if _, _, err := r.Read(); !errors.As(err, new(*csv.ParseError)) {
+		t.Fatal("Expected a parse error, got", err)
+	}
+	_, errs, err := r.Read()
+	if err != nil || len(errs) != 1 || errs[0].Row != 4 || errs[0].Line != 4 {
+		t.Fatal("Expected an error in row 4", errs, err)
+	}
+}
+
+type csvTestProbeBase struct {
+	ID int `validation:"min=1"`
+}
+
+type csvTestProbe struct {
+	*csvTestProbeBase
+	Name string `validation:"min_length=2"`
+}
+
+func TestValidateCSVUnexportedEmbedded(t *testing.T) {
+	errs, err := ValidateCSV(strings.NewReader("ID,Name\n5,x\n"), reflect.TypeOf(csvTestProbe{}), Options{})
+	expected := []CSVError{{Row: 2, Line: 2, Column: "Name", Code: "min_length", Message: "must be at least 2 characters"}}
+	if err != nil || !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("Expected %v, got %v (%v)", expected, errs, err)
+	}
+}
+
+func TestValidateCSV(t *testing.T) {
+	vm := csvTestMap()
+	customerType := reflect.TypeOf(csvTestCustomer{})
+	errs, err := vm.ValidateCSV(strings.NewReader("name,age,plan,e-mail\nBob,18,pro,bob@example.com\nAl,17,pro,al@example.com\nAlice,20,gold,alice@example.com\n"), customerType, Options{})
+	expected := []CSVError{
+		{Row: 3, Line: 3, Column: "age", Code: "min", Message: "must be greater than or equal to 18"},
+		{Row: 4, Line: 4, Column: "plan", Code: "lookup", Message: "is not a valid plan"},
+	}
+	if err != nil || !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("Expected %v, got %v (%v)", expected, errs, err)
+	}
+
+	errs, err = vm.ValidateCSV(strings.NewReader("name,age,e-mail\nAl,17,al@example.com\nBob\n"), customerType, Options{})
+	if _, ok := err.(*csv.ParseError); !ok || len(errs) != 2 {
+		t.Fatal("Expected a parse error after the first record", errs, err)
+	}
+
+	for _, data := range []string{"", "name,tags\n"} {
+		if _, err := vm.ValidateCSV(strings.NewReader(data), customerType, Options{}); err == nil {
+			t.Errorf("%q: expected an error", data)
+		}
+	}
+	if _, err := vm.ValidateCSV(strings.NewReader("a\n"), reflect.TypeOf(""), Options{}); err == nil {
+		t.Fatal("Expected an error for a string type")
+	}
+}
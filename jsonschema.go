@@ -0,0 +1,374 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSONSchemaDialect is the JSON Schema draft ExportJSONSchema generates.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Enumerator can optionally be implemented by a type to list the values it
+// allows, which ExportJSONSchema and ExportOpenAPI describe as enum.
+type Enumerator interface {
+	Enum() []interface{}
+}
+
+// JSONSchema is a JSON Schema document, or a schema within one, holding the
+// keywords needed to describe Go types and their validations.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Minimum              json.Number            `json:"minimum,omitempty"`
+	Maximum              json.Number            `json:"maximum,omitempty"`
+	MinLength            *int                   `json:"minLength,omitempty"`
+	MaxLength            *int                   `json:"maxLength,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	Defs                 map[string]*JSONSchema `json:"$defs,omitempty"`
+}
+
+// ExportJSONSchema generates the JSON Schema of objectType using
+// DefaultValidationMap. See Map.ExportJSONSchema.
+func ExportJSONSchema(objectType reflect.Type) (*JSONSchema, error) {
+	return DefaultMap.ExportJSONSchema(objectType)
+}
+
+// ExportJSONSchema generates a JSON Schema (draft 2020-12) document
+// describing the JSON encoding of the struct type objectType, or the struct
+// type it points to, with its validations:
+//
+//	min, max             minimum, maximum
+//	min_length           minLength
+//	max_length           maxLength
+//	format=email         format: email
+//	format=regexp:       pattern
+//
+// Properties are named like encoding/json names them. Types implementing
+// Enumerator list their values in enum. Other structs held by the fields are
+// described in $defs and referenced by their type name.
+// Validations without a JSON Schema counterpart, such as lookup, are left
+// out. A *CompileError is returned for invalid validation tags.
+func (vm *Map) ExportJSONSchema(objectType reflect.Type) (*JSONSchema, error) {
+	for objectType != nil && objectType.Kind() == reflect.Ptr {
+		objectType = objectType.Elem()
+	}
+	if objectType == nil || objectType.Kind() != reflect.Struct {
+		return nil, errors.New("validation: ExportJSONSchema requires a struct type")
+	}
+
+	e := newSchemaExporter(vm, "#/$defs/")
+	e.root = objectType
+	schema, err := e.object(objectType)
+	if err != nil {
+		return nil, err
+	}
+	schema.Schema = JSONSchemaDialect
+	if len(e.defs) > 0 {
+		schema.Defs = e.defs
+	}
+	return schema, nil
+}
+
+// schemaExporter generates the schemas of struct types and the types
+// reachable from them. Named structs are added to defs and referenced with
+// prefix, except root, which is referenced as the document itself.
+type schemaExporter struct {
+	vm     *Map
+	prefix string
+	root   reflect.Type
+	names  map[reflect.Type]string
+	defs   map[string]*JSONSchema
+
+	// required lists the properties without omitempty which are not
+	// pointers as required.
+	required bool
+}
+
+func newSchemaExporter(vm *Map, prefix string) *schemaExporter {
+	return &schemaExporter{
+		vm:     vm,
+		prefix: prefix,
+		names:  map[reflect.Type]string{},
+		defs:   map[string]*JSONSchema{},
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// object generates the schema of the struct type objectType.
+func (e *schemaExporter) object(objectType reflect.Type) (*JSONSchema, error) {
+	rules, err := e.vm.Rules(objectType)
+	if err != nil {
+		return nil, err
+	}
+	schema := &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}}
+	for _, field := range jsonFields(objectType) {
+		property, err := e.schema(field.Type)
+		if err != nil {
+			return nil, err
+		}
+		if property == nil {
+			continue
+		}
+		for _, rule := range rules {
+			if reflect.DeepEqual(rule.Index, field.Index) {
+				applyRule(property, rule)
+			}
+		}
+		schema.Properties[field.name] = property
+		if e.required && !field.omitEmpty && field.Type.Kind() != reflect.Ptr {
+			schema.Required = append(schema.Required, field.name)
+		}
+	}
+	return schema, nil
+}
+
+// schema generates the schema of values of typ. nil is returned for types
+// JSON cannot represent.
+func (e *schemaExporter) schema(typ reflect.Type) (*JSONSchema, error) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	schema, err := e.schemaOfKind(typ)
+	if schema != nil && schema.Ref == "" {
+		if enum, ok := reflect.Zero(typ).Interface().(Enumerator); ok {
+			schema.Enum = enum.Enum()
+		} else if enum, ok := reflect.New(typ).Interface().(Enumerator); ok {
+			schema.Enum = enum.Enum()
+		}
+	}
+	return schema, err
+}
+
+func (e *schemaExporter) schemaOfKind(typ reflect.Type) (*JSONSchema, error) {
+	switch typ.Kind() {
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}, nil
+	case reflect.String:
+		return &JSONSchema{Type: "string"}, nil
+	case reflect.Interface:
+		return &JSONSchema{}, nil
+	case reflect.Slice, reflect.Array:
+		if typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchema{Type: "string", Format: "byte"}, nil
+		}
+		items, err := e.schema(typ.Elem())
+		if err != nil || items == nil {
+			return nil, err
+		}
+		return &JSONSchema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if typ.Key().Kind() != reflect.String {
+			return nil, nil
+		}
+		values, err := e.schema(typ.Elem())
+		if err != nil || values == nil {
+			return nil, err
+		}
+		return &JSONSchema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		if typ == timeType {
+			return &JSONSchema{Type: "string", Format: "date-time"}, nil
+		}
+		return e.ref(typ)
+	}
+	return nil, nil
+}
+
+// ref returns a reference to the schema of the struct type typ, adding it
+// to $defs first if needed. Anonymous structs are described in place.
+func (e *schemaExporter) ref(typ reflect.Type) (*JSONSchema, error) {
+	if typ == e.root {
+		return &JSONSchema{Ref: "#"}, nil
+	}
+	if typ.Name() == "" {
+		return e.object(typ)
+	}
+	if name, ok := e.names[typ]; ok {
+		return &JSONSchema{Ref: e.prefix + name}, nil
+	}
+
+	name := typ.Name()
+	if _, taken := e.defs[name]; taken {
+		name = strings.ReplaceAll(typ.PkgPath(), "/", ".") + "." + name
+	}
+	e.names[typ] = name
+	e.defs[name] = nil // reserve the name for recursive types
+	schema, err := e.object(typ)
+	if err != nil {
+		return nil, err
+	}
+	e.defs[name] = schema
+	return &JSONSchema{Ref: e.prefix + name}, nil
+}
+
+// applyRule adds the JSON Schema counterpart of rule, if any, to schema.
+func applyRule(schema *JSONSchema, rule RuleDescriptor) {
+	switch rule.Name {
+	case "min":
+		schema.Minimum = jsonNumber(rule.Params["value"], rule.Type)
+	case "max":
+		schema.Maximum = jsonNumber(rule.Params["value"], rule.Type)
+	case "min_length":
+		if length, ok := rule.Params["length"].(int); ok {
+			schema.MinLength = &length
+		}
+	case "max_length":
+		if length, ok := rule.Params["length"].(int); ok {
+			schema.MaxLength = &length
+		}
+	case "format":
+		if format, ok := rule.Params["format"].(string); ok {
+			schema.Format = format
+		} else if pattern, ok := rule.Params["pattern"].(string); ok {
+			schema.Pattern = pattern
+		}
+	}
+}
+
+// jsonNumber formats value, a limit of a field of type typ, as a JSON number.
+func jsonNumber(value interface{}, typ reflect.Type) json.Number {
+	switch value := value.(type) {
+	case int64:
+		return json.Number(strconv.FormatInt(value, 10))
+	case uint64:
+		return json.Number(strconv.FormatUint(value, 10))
+	case float64:
+		// Limits of float32 fields were rounded to float32, so they are
+		// formatted with as many digits as that precision needs.
+		if typ.Kind() == reflect.Float32 {
+			return json.Number(strconv.FormatFloat(value, 'g', -1, 32))
+		}
+		return json.Number(strconv.FormatFloat(value, 'g', -1, 64))
+	}
+	return json.Number(fmt.Sprint(value))
+}
+
+// jsonField is a struct field as encoded by encoding/json. tagged reports
+// whether its name comes from its json tag.
+type jsonField struct {
+	reflect.StructField
+	name      string
+	omitEmpty bool
+	tagged    bool
+}
+
+// jsonFields returns the fields of the struct type typ encoding/json
+// encodes, in declaration order. Fields of embedded structs without a JSON
+// name are promoted, except through pointers to unexported structs, which
+// encoding/json cannot decode into. Of fields with the same name, the
+// shallowest is kept if it is the only one at its depth or the only tagged
+// one there; otherwise none is, like encoding/json does.
+func jsonFields(typ reflect.Type) []jsonField {
+	var all []jsonField
+	var walk func(typ reflect.Type, index []int, visited map[reflect.Type]bool)
+	walk = func(typ reflect.Type, index []int, visited map[reflect.Type]bool) {
+		if visited[typ] {
+			return
+		}
+		visited[typ] = true
+		defer delete(visited, typ)
+
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			field.Index = append(append([]int(nil), index...), i)
+			tag := field.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, options, _ := strings.Cut(tag, ",")
+
+			if field.Anonymous && name == "" {
+				embedded := field.Type
+				if embedded.Kind() == reflect.Ptr {
+					// encoding/json cannot allocate pointers to
+					// unexported structs, so it decodes none of
+					// their fields.
+					if !field.IsExported() {
+						continue
+					}
+					embedded = embedded.Elem()
+				}
+				if embedded.Kind() == reflect.Struct {
+					walk(embedded, field.Index, visited)
+					continue
+				}
+			}
+			if !field.IsExported() {
+				continue
+			}
+			tagged := name != ""
+			if !tagged {
+				name = field.Name
+			}
+			all = append(all, jsonField{
+				StructField: field,
+				name:        name,
+				omitEmpty:   strings.Contains(","+options+",", ",omitempty,"),
+				tagged:      tagged,
+			})
+		}
+	}
+	walk(typ, nil, map[reflect.Type]bool{})
+
+	byName := map[string][]int{}
+	for i, field := range all {
+		byName[field.name] = append(byName[field.name], i)
+	}
+	var fields []jsonField
+	for i, field := range all {
+		if dominantField(all, byName[field.name]) == i {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// dominantField returns the index of the field of fields, listed by
+// candidates, that encoding/json encodes under their common name, or -1 if
+// there is none.
+func dominantField(fields []jsonField, candidates []int) int {
+	depth := -1
+	for _, i := range candidates {
+		if d := len(fields[i].Index); depth < 0 || d < depth {
+			depth = d
+		}
+	}
+	dominant, tagged := -1, -1
+	count, taggedCount := 0, 0
+	for _, i := range candidates {
+		if len(fields[i].Index) != depth {
+			continue
+		}
+		dominant = i
+		count++
+		if fields[i].tagged {
+			tagged = i
+			taggedCount++
+		}
+	}
+	if count == 1 {
+		return dominant
+	}
+	if taggedCount == 1 {
+		return tagged
+	}
+	return -1
+}
@@ -0,0 +1,408 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ImportJSONSchema creates a Schema from a JSON Schema document using
+// DefaultValidationMap. See Map.ImportJSONSchema.
+func ImportJSONSchema(data []byte) (*Schema, error) {
+	return DefaultMap.ImportJSONSchema(data)
+}
+
+// ImportJSONSchema creates a Schema from the JSON Schema document data,
+// building its rules with the validations of vm:
+//
+//	minimum, maximum     min, max
+//	minLength            min_length
+//	maxLength            max_length
+//	format: email        format=email
+//	pattern              format=regexp:
+//
+// The type, properties, required, additionalProperties and items keywords
+// are checked by the Schema itself and $ref may refer to any schema within
+// the document, e.g. under $defs. Annotations such as title and unknown
+// formats are ignored, while other keywords are reported as unsupported, so
+// a Schema never checks less than the document asks for.
+func (vm *Map) ImportJSONSchema(data []byte) (*Schema, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var root interface{}
+	if err := decoder.Decode(&root); err != nil {
+		return nil, fmt.Errorf("validation: invalid JSON Schema: %v", err)
+	}
+
+	im := &schemaImporter{vm: vm, root: root, refs: map[string]*Schema{}}
+	if node, ok := root.(map[string]interface{}); ok && node["$ref"] == nil {
+		schema := &Schema{vm: vm}
+		im.refs["#"] = schema
+		if err := im.fill(schema, node, "#"); err != nil {
+			return nil, err
+		}
+		return schema, nil
+	}
+	return im.parse(root, "#")
+}
+
+// schemaImporter creates the schemas of a JSON Schema document.
+type schemaImporter struct {
+	vm   *Map
+	root interface{}
+
+	// refs holds the schemas created for references by JSON pointer.
+	refs map[string]*Schema
+}
+
+// schemaAnnotations are the keywords which do not affect validation.
+var schemaAnnotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "$defs": true, "definitions": true,
+	"title": true, "description": true, "default": true, "examples": true,
+	"deprecated": true, "readOnly": true, "writeOnly": true,
+}
+
+// parse creates the schema of node, found at pointer.
+func (im *schemaImporter) parse(node interface{}, pointer string) (*Schema, error) {
+	switch node := node.(type) {
+	case bool:
+		if node {
+			return &Schema{vm: im.vm}, nil
+		}
+		return nil, schemaError(pointer, "the false schema is not supported")
+	case map[string]interface{}:
+		if ref, ok := node["$ref"]; ok {
+			for keyword := range node {
+				if keyword != "$ref" && !schemaAnnotations[keyword] {
+					return nil, schemaError(pointer, "$ref cannot be combined with "+keyword)
+				}
+			}
+			ref, ok := ref.(string)
+			if !ok {
+				return nil, schemaError(pointer, "$ref must be a string")
+			}
+			return im.ref(ref, pointer)
+		}
+		schema := &Schema{vm: im.vm}
+		return schema, im.fill(schema, node, pointer)
+	}
+	return nil, schemaError(pointer, "a schema must be an object or a boolean")
+}
+
+// ref returns the schema ref, found at pointer, refers to.
+func (im *schemaImporter) ref(ref, pointer string) (*Schema, error) {
+	if schema, ok := im.refs[ref]; ok {
+		return schema, nil
+	}
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, schemaError(pointer, "$ref "+ref+" does not refer to the document")
+	}
+	node := im.root
+	for _, token := range strings.Split(ref[2:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, schemaError(pointer, "$ref "+ref+" does not exist")
+		}
+		if node, ok = object[token]; !ok {
+			return nil, schemaError(pointer, "$ref "+ref+" does not exist")
+		}
+	}
+	object, ok := node.(map[string]interface{})
+	if !ok || object["$ref"] != nil {
+		return nil, schemaError(pointer, "$ref "+ref+" must refer to a schema object without $ref")
+	}
+
+	// The schema is registered before it is filled for recursive references.
+	schema := &Schema{vm: im.vm}
+	im.refs[ref] = schema
+	return schema, im.fill(schema, object, ref)
+}
+
+// fill sets up schema from node, found at pointer.
+func (im *schemaImporter) fill(schema *Schema, node map[string]interface{}, pointer string) error {
+	keywords := make([]string, 0, len(node))
+	for keyword := range node {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+
+	if err := im.fillType(schema, node["type"], pointer); err != nil {
+		return err
+	}
+
+	var rules []Rule
+	for _, keyword := range keywords {
+		value := node[keyword]
+		at := pointer + "/" + keyword
+		switch keyword {
+		case "type":
+		case "minimum", "maximum":
+			number, ok := value.(json.Number)
+			if !ok {
+				return schemaError(at, "must be a number")
+			}
+			if schema.typ != "integer" && schema.typ != "number" {
+				return schemaError(at, "requires type integer or number")
+			}
+			name := "min"
+			if keyword == "maximum" {
+				name = "max"
+			}
+			rules = append(rules, Rule{Name: name, Options: numberOption(number, schema.typ)})
+		case "minLength", "maxLength":
+			number, ok := value.(json.Number)
+			if !ok {
+				return schemaError(at, "must be a number")
+			}
+			if schema.typ != "string" {
+				return schemaError(at, "requires type string")
+			}
+			name := "min_length"
+			if keyword == "maxLength" {
+				name = "max_length"
+			}
+			rules = append(rules, Rule{Name: name, Options: number.String()})
+		case "pattern":
+			pattern, ok := value.(string)
+			if !ok {
+				return schemaError(at, "must be a string")
+			}
+			if schema.typ != "string" {
+				return schemaError(at, "requires type string")
+			}
+			rules = append(rules, Rule{Name: "format", Options: "regexp:" + pattern})
+		case "format":
+			if value == "email" {
+				if schema.typ != "string" {
+					return schemaError(at, "requires type string")
+				}
+				rules = append(rules, Rule{Name: "format", Options: "email"})
+			}
+		case "properties":
+			properties, ok := value.(map[string]interface{})
+			if !ok {
+				return schemaError(at, "must be an object")
+			}
+			names := make([]string, 0, len(properties))
+			for name := range properties {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				property, err := im.parse(properties[name], at+"/"+escapePointer(name))
+				if err != nil {
+					return err
+				}
+				schema.properties = append(schema.properties, schemaProperty{name: name, schema: property})
+			}
+		case "required":
+		case "additionalProperties":
+			switch value := value.(type) {
+			case bool:
+				schema.closed = !value
+			default:
+				additional, err := im.parse(value, at)
+				if err != nil {
+					return err
+				}
+				schema.additional = additional
+			}
+		case "items":
+			items, err := im.parse(value, at)
+			if err != nil {
+				return err
+			}
+			schema.items = items
+		default:
+			if !schemaAnnotations[keyword] {
+				return schemaError(at, "is not supported")
+			}
+		}
+	}
+
+	if required, ok := node["required"]; ok {
+		names, ok := required.([]interface{})
+		if !ok {
+			return schemaError(pointer+"/required", "must be an array of strings")
+		}
+		for _, name := range names {
+			name, ok := name.(string)
+			if !ok {
+				return schemaError(pointer+"/required", "must be an array of strings")
+			}
+			if property := schema.property(name); property != nil {
+				property.required = true
+			} else {
+				schema.properties = append(schema.properties, schemaProperty{name: name, schema: &Schema{vm: im.vm}, required: true})
+			}
+		}
+	}
+
+	if len(rules) > 0 {
+		validations, err := im.vm.BuildRules(rules, schema.kind)
+		if err != nil {
+			return schemaError(pointer, err.Error())
+		}
+		for i, validation := range validations {
+			schema.rules = append(schema.rules, schemaRule{Interface: validation, rule: rules[i]})
+		}
+	}
+	return nil
+}
+
+// fillType sets the type of schema from the type keyword value, which may
+// list null besides a single type.
+func (im *schemaImporter) fillType(schema *Schema, value interface{}, pointer string) error {
+	var types []interface{}
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case string:
+		types = []interface{}{value}
+	case []interface{}:
+		types = value
+	default:
+		return schemaError(pointer+"/type", "must be a string or an array of strings")
+	}
+	for _, typ := range types {
+		switch typ {
+		case "null":
+			schema.nullable = true
+		case "string", "integer", "number", "boolean", "object", "array":
+			if schema.typ != "" {
+				return schemaError(pointer+"/type", "may only list null besides one type")
+			}
+			schema.typ = typ.(string)
+			schema.kind = schemaKinds[schema.typ]
+		default:
+			return schemaError(pointer+"/type", fmt.Sprintf("has unknown type %v", typ))
+		}
+	}
+	if schema.typ == "" {
+		// Only null is valid.
+		schema.typ = "null"
+	}
+	return nil
+}
+
+// numberOption formats number as the option of a min or max rule of the
+// JSON type typ. Integral numbers such as 1e3 are written as integers for
+// integer types.
+func numberOption(number json.Number, typ string) string {
+	if typ == "integer" {
+		if f, err := number.Float64(); err == nil && f == math.Trunc(f) && math.Abs(f) < 1<<63 {
+			return strconv.FormatInt(int64(f), 10)
+		}
+	}
+	return number.String()
+}
+
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+func schemaError(pointer, message string) error {
+	return fmt.Errorf("validation: JSON Schema %s: %s", pointer, message)
+}
+
+// Bind sets the rules of the properties of s as the rules of the fields of
+// the struct type objectType, or the struct type it points to, with the same
+// JSON name on the Map of s, replacing their validation tags. Properties
+// holding objects are bound to the struct types of their fields in the same
+// way. Fields without a property keep their tags. The type, required and
+// additionalProperties keywords have no rule counterpart and are not bound.
+// Like SetRules, Bind changes nothing if an error is returned.
+func (s *Schema) Bind(objectType reflect.Type) error {
+	changes := ruleSet{}
+	if err := s.bind(objectType, changes, map[boundSchema]bool{}); err != nil {
+		return err
+	}
+	return s.vm.updateRules(changes)
+}
+
+// boundSchema is a schema bound to a struct type.
+type boundSchema struct {
+	schema     *Schema
+	objectType reflect.Type
+}
+
+// bind adds the rules of s for objectType, and the struct types of its
+// fields, to changes. A type may only be bound to the same rules twice.
+func (s *Schema) bind(objectType reflect.Type, changes ruleSet, bound map[boundSchema]bool) error {
+	for objectType != nil && objectType.Kind() == reflect.Ptr {
+		objectType = objectType.Elem()
+	}
+	if objectType == nil || objectType.Kind() != reflect.Struct {
+		return errors.New("validation: Bind requires a struct type")
+	}
+	if bound[boundSchema{s, objectType}] {
+		return nil
+	}
+	bound[boundSchema{s, objectType}] = true
+
+	fields := map[string]jsonField{}
+	for _, field := range jsonFields(objectType) {
+		fields[field.name] = field
+	}
+	tr := &typeRules{fields: map[string][]Rule{}}
+	var nested []func() error
+	for _, property := range s.properties {
+		field, ok := fields[property.name]
+		if !ok {
+			return &CompileError{
+				Type:  objectType,
+				Field: property.name,
+				Err:   &ValidationError{Key: property.name, Message: "has no field with this JSON name"},
+			}
+		}
+		var rules []Rule
+		for _, rule := range property.schema.rules {
+			rules = append(rules, rule.rule)
+		}
+		tr.fields[field.Name] = rules
+
+		schema, fieldType := property.schema, field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if schema.items != nil && (fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array) {
+			schema, fieldType = schema.items, fieldType.Elem()
+			if len(schema.rules) > 0 {
+				return &CompileError{
+					Type:  objectType,
+					Field: field.Name,
+					Err:   &ValidationError{Key: property.name, Message: "cannot bind rules of items"},
+				}
+			}
+		}
+		if len(schema.properties) > 0 {
+			nested = append(nested, func() error {
+				return schema.bind(fieldType, changes, bound)
+			})
+		}
+	}
+
+	if existing, ok := changes[objectType]; ok {
+		if !reflect.DeepEqual(existing.fields, tr.fields) {
+			return &CompileError{
+				Type: objectType,
+				Err:  &ValidationError{Key: objectType.String(), Message: "is bound to different rules"},
+			}
+		}
+	} else {
+		changes[objectType] = tr
+	}
+	for _, bind := range nested {
+		if err := bind(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
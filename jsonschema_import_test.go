@@ -0,0 +1,196 @@
+package validation
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const importTestSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"title": "Customer",
+	"type": "object",
+	"required": ["email", "age"],
+	"additionalProperties": false,
+	"properties": {
+		"email": {"type": "string", "format": "email"},
+		"age": {"type": "integer", "minimum": 18, "maximum": 1.2e2},
+		"score": {"type": ["number", "null"], "maximum": 10},
+		"code": {"type": "string", "pattern": "^[A-Z]+$", "minLength": 2, "maxLength": 4},
+		"address": {"$ref": "#/$defs/address"},
+		"previous": {"type": "array", "items": {"$ref": "#/$defs/address"}},
+		"referrer": {"$ref": "#"}
+	},
+	"$defs": {
+		"address": {
+			"type": "object",
+			"properties": {
+				"street": {"type": "string", "minLength": 2, "description": "Street and number"}
+			}
+		}
+	}
+}`
+
+func TestImportJSONSchema(t *testing.T) {
+	schema, err := ImportJSONSchema([]byte(importTestSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var document interface{}
+	json.Unmarshal([]byte(`{
+		"email": "bob@example.com",
+		"age": 30,
+		"score": null,
+		"code": "ABC",
+		"address": {"street": "Main 1"},
+		"previous": [{"street": "Elm 2"}],
+		"referrer": {"email": "alice@example.com", "age": 40}
+	}`), &document)
+	if ok, errs := schema.Validate(document); !ok {
+		t.Fatal("Expected the document to be valid", errs)
+	}
+
+	json.Unmarshal([]byte(`{
+		"email": "bob",
+		"score": "high",
+		"code": "abcdef",
+		"address": {"street": "M"},
+		"previous": [{"street": "Elm 2"}, {"street": 3}],
+		"referrer": {"email": "alice@example.com", "age": 12.5},
+		"phone": "555"
+	}`), &document)
+	ok, errs := schema.Validate(document)
+	expected := []ValidationError{
+		{Key: "address.street", Message: "must be at least 2 characters"},
+		{Key: "age", Message: "is required"},
+		{Key: "code", Message: "must be no more than 4 characters"},
+		{Key: "code", Message: "does not match regexp format"},
+		{Key: "email", Message: "does not match email format"},
+		{Key: "previous[1].street", Message: "must be of type string"},
+		{Key: "referrer.age", Message: "must be of type integer"},
+		{Key: "score", Message: "must be of type number"},
+		{Key: "phone", Message: "is not allowed"},
+	}
+	if ok || len(errs) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, errs)
+	}
+	for i := range expected {
+		if errs[i].Key != expected[i].Key || !strings.HasPrefix(errs[i].Message, expected[i].Message) {
+			t.Fatalf("Error %d: expected %v, got %v", i, expected[i], errs)
+		}
+	}
+
+	if ok, errs := schema.Validate([]interface{}{}); ok || errs[0].Key != "object" {
+		t.Fatal("Expected the document itself to be invalid", errs)
+	}
+}
+
+func TestImportJSONSchemaErrors(t *testing.T) {
+	invalid := map[string]string{
+		"invalid JSON":        `{`,
+		"unsupported keyword": `{"type": "string", "enum": ["a"]}`,
+		"rule without type":   `{"minimum": 1}`,
+		"rule of other type":  `{"type": "string", "minimum": 1}`,
+		"invalid pattern":     `{"type": "string", "pattern": "[a-"}`,
+		"conflicting limits":  `{"type": "integer", "minimum": 10, "maximum": 5}`,
+		"fractional limit":    `{"type": "integer", "minimum": 1.5}`,
+		"missing ref":         `{"properties": {"a": {"$ref": "#/$defs/a"}}}`,
+		"external ref":        `{"$ref": "https://example.com/schema.json"}`,
+		"two types":           `{"type": ["string", "integer"]}`,
+		"false schema":        `{"properties": {"a": false}}`,
+	}
+	for name, src := range invalid {
+		if _, err := ImportJSONSchema([]byte(src)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	_, err := ImportJSONSchema([]byte(`{"properties": {"a": {"type": "string", "minLength": "x"}}}`))
+	if err == nil || !strings.Contains(err.Error(), "#/properties/a/minLength") {
+		t.Fatal("Expected the error to point at the keyword", err)
+	}
+}
+
+type importTestAddress struct {
+	Street string `json:"street"`
+}
+
+type importTestCustomer struct {
+	Email    string              `json:"email" validation:"min_length=1"`
+	Age      uint8               `json:"age"`
+	Nickname string              `json:"nickname" validation:"max_length=10"`
+	Address  *importTestAddress  `json:"address"`
+	Previous []importTestAddress `json:"previous"`
+}
+
+func TestSchemaBind(t *testing.T) {
+	vm := NewMap(&DefaultMap)
+	schema, err := vm.ImportJSONSchema([]byte(importTestSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	type partial struct {
+		Email string `json:"email"`
+	}
+	if err := schema.Bind(reflect.TypeOf(partial{})); err == nil {
+		t.Fatal("Expected an error for properties without field")
+	}
+
+	customerSchema, err := vm.ImportJSONSchema([]byte(`{
+		"type": "object",
+		"properties": {
+			"email": {"type": "string", "format": "email"},
+			"age": {"type": "integer", "minimum": 18},
+			"address": {"type": "object", "properties": {"street": {"type": "string", "minLength": 2}}},
+			"previous": {"type": "array", "items": {"type": "object", "properties": {"street": {"type": "string", "minLength": 2}}}}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := customerSchema.Bind(reflect.TypeOf(importTestCustomer{})); err != nil {
+		t.Fatal(err)
+	}
+
+	customer := importTestCustomer{
+		Email:    "bob",
+		Age:      12,
+		Nickname: "a very long nickname",
+		Address:  &importTestAddress{Street: "M"},
+		Previous: []importTestAddress{{Street: "Elm"}, {Street: "N"}},
+	}
+	ok, errs := vm.IsValidWithOptions(customer, Options{Nested: true})
+	keys := []string{"Nickname", "Age", "Email", "Previous[1].Street", "Address.Street"}
+	if ok || len(errs) != len(keys) {
+		t.Fatal("Expected errors of the bound rules and the remaining tags", errs)
+	}
+	for i, key := range keys {
+		if errs[i].Key != key {
+			t.Fatalf("Error %d: expected key %s, got %v", i, key, errs)
+		}
+	}
+
+	conflicting, err := vm.ImportJSONSchema([]byte(`{"properties": {
+		"address": {"properties": {"street": {"type": "string", "minLength": 2}}},
+		"previous": {"items": {"properties": {"street": {"type": "string", "minLength": 3}}}}
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conflicting.Bind(reflect.TypeOf(importTestCustomer{})); err == nil {
+		t.Fatal("Expected an error for a type bound to different rules")
+	}
+
+	tooLarge, err := vm.ImportJSONSchema([]byte(`{"properties": {"age": {"type": "integer", "maximum": 1000}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tooLarge.Bind(reflect.TypeOf(importTestCustomer{})); err == nil {
+		t.Fatal("Expected an error for a limit out of the range of the field")
+	}
+	if ok, errs := vm.IsValidWithOptions(customer, Options{Nested: true}); ok || len(errs) != len(keys) {
+		t.Fatal("Expected the previous rules to stay in use", errs)
+	}
+}
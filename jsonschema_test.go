@@ -0,0 +1,153 @@
+package validation
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+type schemaTestAddress struct {
+	Street string `json:"street" validation:"min_length=2 max_length=40"`
+	Zip    string `json:"zip,omitempty" validation:"format=regexp:^[0-9]{5}$"`
+}
+
+type schemaTestBase struct {
+	ID uint64 `json:"id" validation:"min=1"`
+}
+
+type schemaTestCustomer struct {
+	schemaTestBase
+	Email    string              `json:"email" validation:"format=email"`
+	Score    float32             `json:"score" validation:"min=-1.5 max=10"`
+	Address  *schemaTestAddress  `json:"address"`
+	Previous []schemaTestAddress `json:"previous"`
+	Tags     map[string]string   `json:"tags"`
+	Created  time.Time           `json:"created"`
+	Referrer *schemaTestCustomer `json:"referrer,omitempty"`
+	SKU      string              `json:"sku" validation:"lookup=sku"`
+	Internal string              `json:"-"`
+	Untagged bool
+	Callback func() `json:"callback"`
+	secret   string `validation:"min_length=1"`
+}
+
+func TestExportJSONSchema(t *testing.T) {
+	schema, err := ExportJSONSchema(reflect.TypeOf(&schemaTestCustomer{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"Untagged": {"type": "boolean"},
+			"address": {"$ref": "#/$defs/schemaTestAddress"},
+			"created": {"type": "string", "format": "date-time"},
+			"email": {"type": "string", "format": "email"},
+			"id": {"type": "integer", "minimum": 1},
+			"previous": {"type": "array", "items": {"$ref": "#/$defs/schemaTestAddress"}},
+			"referrer": {"$ref": "#"},
+			"score": {"type": "number", "minimum": -1.5, "maximum": 10},
+			"sku": {"type": "string"},
+			"tags": {"type": "object", "additionalProperties": {"type": "string"}}
+		},
+		"$defs": {
+			"schemaTestAddress": {
+				"type": "object",
+				"properties": {
+					"street": {"type": "string", "minLength": 2, "maxLength": 40},
+					"zip": {"type": "string", "pattern": "^[0-9]{5}$"}
+				}
+			}
+		}
+	}`
+	var a, e interface{}
+	json.Unmarshal(actual, &a)
+	json.Unmarshal([]byte(expected), &e)
+	if !reflect.DeepEqual(a, e) {
+		t.Fatalf("Unexpected schema:\n%s", actual)
+	}
+}
+
+type schemaTestRate struct {
+	Rate float32 `json:"rate" validation:"min=0.1 max=0.3"`
+}
+
+func TestExportJSONSchemaFloat32(t *testing.T) {
+	schema, err := ExportJSONSchema(reflect.TypeOf(schemaTestRate{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := json.Marshal(schema.Properties)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"rate":{"type":"number","minimum":0.1,"maximum":0.3}}`
+	if string(actual) != expected {
+		t.Fatalf("Expected %s, got %s", expected, actual)
+	}
+}
+
+type schemaTestLeft struct {
+	X string
+	Y string
+}
+
+type schemaTestRight struct {
+	X string
+	Z string `json:"Y"`
+}
+
+type schemaTestConflict struct {
+	schemaTestLeft
+	schemaTestRight
+}
+
+func TestExportJSONSchemaConflicts(t *testing.T) {
+	schema, err := ExportJSONSchema(reflect.TypeOf(schemaTestConflict{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	encoded, err := json.Marshal(schemaTestConflict{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var object map[string]interface{}
+	json.Unmarshal(encoded, &object)
+	var expected []string
+	for name := range object {
+		expected = append(expected, name)
+	}
+	sort.Strings(expected)
+
+	if !reflect.DeepEqual(names, expected) || !reflect.DeepEqual(names, []string{"Y"}) {
+		t.Fatalf("Expected the properties %v encoding/json encodes, got %v", expected, names)
+	}
+}
+
+func TestExportJSONSchemaErrors(t *testing.T) {
+	if _, err := ExportJSONSchema(reflect.TypeOf("")); err == nil {
+		t.Fatal("Expected an error for a non-struct type")
+	}
+	type invalid struct {
+		Nested struct {
+			A int `validation:"min=abc"`
+		}
+	}
+	if _, err := ExportJSONSchema(reflect.TypeOf(invalid{})); err == nil {
+		t.Fatal("Expected an error for an invalid tag of a nested struct")
+	}
+}
@@ -0,0 +1,219 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// Resolver checks values against an external data source, e.g. that a SKU
+// exists or that a username is not taken. Resolvers are registered on a Map
+// by name and referenced from tags with lookup=name.
+type Resolver interface {
+	// Resolve reports for every key whether it is valid. The returned slice
+	// must have the same length as keys.
+	Resolve(ctx context.Context, keys []interface{}) ([]bool, error)
+}
+
+// AddResolver registers the resolver used by lookup=name validations
+// using DefaultValidationMap.
+func AddResolver(name string, resolver Resolver) {
+	DefaultMap.AddResolver(name, resolver)
+}
+
+// AddResolver registers the resolver used by lookup=name validations. If more
+// than one resolver registers with the same name, the last one wins.
+func (vm *Map) AddResolver(name string, resolver Resolver) {
+	vm.resolvers.Store(name, resolver)
+}
+
+// resolver returns the resolver registered under name on vm or, failing
+// that, on the closest of its parents.
+func (vm *Map) resolver(name string) (Resolver, bool) {
+	for m := vm; m != nil; m = m.parent {
+		if resolver, ok := m.resolvers.Load(name); ok {
+			resolver, _ := resolver.(Resolver)
+			return resolver, resolver != nil
+		}
+	}
+	return nil, false
+}
+
+type lookupValidation struct {
+	Validation
+	resolver string
+}
+
+// lookup is a single value waiting to be resolved.
+type lookup struct {
+	key   string
+	value interface{}
+
+	// element is the index of the element the lookup belongs to when the
+	// lookups of IsValidAll are resolved together.
+	element int
+}
+
+func newLookupValidation(options string, kind reflect.Kind) (Interface, error) {
+	if len(options) == 0 {
+		return nil, &ValidationError{Key: "lookup", Message: "Has no resolver name"}
+	}
+
+	return &lookupValidation{
+		resolver: options,
+	}, nil
+}
+
+// Parameters returns the name of the resolver as resolver.
+func (v *lookupValidation) Parameters() map[string]interface{} {
+	return map[string]interface{}{"resolver": v.resolver}
+}
+
+// Validate is not used by Map, which resolves lookups in batches. It reports
+// that the value can only be checked through a Resolver.
+func (v *lookupValidation) Validate(value interface{}, obj reflect.Value) *ValidationError {
+	return &ValidationError{
+		Key:     v.FieldName(),
+		Message: "can only be validated by resolver " + v.resolver,
+	}
+}
+
+// addLookups queues the value of field, found at path, for resolution.
+// Every element of a slice or array field is looked up on its own.
+func (r *run) addLookups(v *lookupValidation, field reflect.Value, path string) {
+	switch field.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < field.Len(); i++ {
+			r.addLookup(v.resolver, joinKey(path, v.FieldName()+"["+strconv.Itoa(i)+"]"), fieldValue(field.Index(i)))
+		}
+	default:
+		r.addLookup(v.resolver, joinKey(path, v.FieldName()), fieldValue(field))
+	}
+}
+
+// addLookup queues value, reported under key, to be resolved by the
+// resolver named resolver.
+func (r *run) addLookup(resolver, key string, value interface{}) {
+	r.queueLookup(resolver, lookup{key: key, value: value})
+}
+
+// queueLookup queues l to be resolved by the resolver named resolver.
+func (r *run) queueLookup(resolver string, l lookup) {
+	if r.lookups == nil {
+		r.lookups = map[string][]lookup{}
+	}
+	if _, ok := r.lookups[resolver]; !ok {
+		r.resolverNames = append(r.resolverNames, resolver)
+	}
+	r.lookups[resolver] = append(r.lookups[resolver], l)
+}
+
+// resolve resolves the pending lookups with one call per resolver.
+func (r *run) resolve() {
+	for _, name := range r.resolverNames {
+		pending := r.lookups[name]
+		if len(pending) == 0 {
+			continue
+		}
+		if r.cancelled() {
+			return
+		}
+
+		resolver, ok := r.vm.resolver(name)
+		if !ok {
+			if !r.failLookups(pending, "has no resolver named "+name) {
+				return
+			}
+			continue
+		}
+
+		keys := make([]interface{}, len(pending))
+		for i, l := range pending {
+			keys[i] = l.value
+		}
+		valid, err := resolver.Resolve(r.ctx, keys)
+		if err == nil && len(valid) != len(keys) {
+			err = fmt.Errorf("resolver %s returned %d results for %d keys", name, len(valid), len(keys))
+		}
+		if err != nil {
+			if !r.failLookups(pending, "could not be resolved: "+err.Error()) {
+				return
+			}
+			continue
+		}
+
+		for i, ok := range valid {
+			if !ok && !r.failLookup(pending[i], "is not a valid "+name) {
+				return
+			}
+		}
+	}
+}
+
+// failLookups records message for every pending lookup and reports whether
+// validation should go on.
+func (r *run) failLookups(pending []lookup, message string) bool {
+	for _, l := range pending {
+		if !r.failLookup(l, message) {
+			return false
+		}
+	}
+	return true
+}
+
+// failLookup records message for the lookup l, or for its element if the
+// run collects errors by element, and reports whether validation should go
+// on.
+func (r *run) failLookup(l lookup, message string) bool {
+	err := ValidationError{Key: l.key, Message: message}
+	if r.elements != nil {
+		r.elements[l.element] = append(r.elements[l.element], err)
+		return true
+	}
+	return r.fail(err)
+}
+
+// MemoryResolver is a Resolver backed by an in-memory set of valid keys. It is
+// safe for concurrent use and mostly useful in tests.
+type MemoryResolver struct {
+	keys sync.Map
+}
+
+// NewMemoryResolver creates a MemoryResolver accepting keys.
+func NewMemoryResolver(keys ...interface{}) *MemoryResolver {
+	r := &MemoryResolver{}
+	r.Add(keys...)
+	return r
+}
+
+// Add marks keys as valid.
+func (r *MemoryResolver) Add(keys ...interface{}) {
+	for _, key := range keys {
+		r.keys.Store(key, struct{}{})
+	}
+}
+
+// Remove marks keys as no longer valid.
+func (r *MemoryResolver) Remove(keys ...interface{}) {
+	for _, key := range keys {
+		r.keys.Delete(key)
+	}
+}
+
+// Resolve reports which keys have been added to the resolver.
+func (r *MemoryResolver) Resolve(ctx context.Context, keys []interface{}) ([]bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	valid := make([]bool, len(keys))
+	for i, key := range keys {
+		_, valid[i] = r.keys.Load(key)
+	}
+	return valid, nil
+}
+
+func init() {
+	AddValidation("lookup", newLookupValidation)
+}
@@ -0,0 +1,120 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type countingResolver struct {
+	Resolver
+	calls int
+	keys  int
+}
+
+func (r *countingResolver) Resolve(ctx context.Context, keys []interface{}) ([]bool, error) {
+	r.calls++
+	r.keys += len(keys)
+	return r.Resolver.Resolve(ctx, keys)
+}
+
+type failingResolver struct{}
+
+func (failingResolver) Resolve(ctx context.Context, keys []interface{}) ([]bool, error) {
+	return nil, errors.New("unavailable")
+}
+
+type orderTestType struct {
+	SKU      string   `validation:"lookup=sku"`
+	Extras   []string `validation:"lookup=sku"`
+	Customer string   `validation:"lookup=user"`
+}
+
+func TestLookupBatching(t *testing.T) {
+	vm := Map{}
+	vm.AddValidation("lookup", newLookupValidation)
+	skus := &countingResolver{Resolver: NewMemoryResolver("A1", "B2", "C3")}
+	users := &countingResolver{Resolver: NewMemoryResolver("bob")}
+	vm.AddResolver("sku", skus)
+	vm.AddResolver("user", users)
+
+	obj := orderTestType{
+		SKU:      "A1",
+		Extras:   []string{"B2", "C3"},
+		Customer: "bob",
+	}
+	ok, errs := vm.IsValid(obj)
+	if !ok {
+		t.Fatal("All keys are known and should be valid", errs)
+	}
+	if skus.calls != 1 || skus.keys != 3 {
+		t.Fatalf("Expected one sku call with 3 keys, got %d calls with %d keys", skus.calls, skus.keys)
+	}
+	if users.calls != 1 || users.keys != 1 {
+		t.Fatalf("Expected one user call with 1 key, got %d calls with %d keys", users.calls, users.keys)
+	}
+
+	obj.Extras[1] = "Z9"
+	obj.Customer = "alice"
+	ok, errs = vm.IsValid(obj)
+	if ok || len(errs) != 2 {
+		t.Fatal("Expected 2 errors", errs)
+	}
+	if errs[0].Key != "Customer" || errs[1].Key != "Extras[1]" {
+		t.Fatal("Unexpected error keys", errs)
+	}
+}
+
+func TestLookupResolverErrors(t *testing.T) {
+	type userTestType struct {
+		Name  string `validation:"lookup=user"`
+		Email string `validation:"lookup=email"`
+	}
+	vm := Map{}
+	vm.AddValidation("lookup", newLookupValidation)
+	vm.AddResolver("user", failingResolver{})
+
+	ok, errs := vm.IsValid(userTestType{Name: "bob"})
+	if ok || len(errs) != 2 {
+		t.Fatal("Expected an error for the failing and the missing resolver", errs)
+	}
+	if errs[0].Message != "has no resolver named email" {
+		t.Fatal("Unexpected missing resolver error", errs[0])
+	}
+	if errs[1].Message != "could not be resolved: unavailable" {
+		t.Fatal("Unexpected resolver error", errs[1])
+	}
+}
+
+func TestMemoryResolver(t *testing.T) {
+	r := NewMemoryResolver("a", 1)
+	r.Add("b")
+	r.Remove("a")
+
+	valid, err := r.Resolve(context.Background(), []interface{}{"a", "b", 1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []bool{false, true, true, false}
+	for i := range expected {
+		if valid[i] != expected[i] {
+			t.Fatalf("Key %d: expected %v got %v", i, expected[i], valid[i])
+		}
+	}
+}
+
+func TestLookupInheritedResolver(t *testing.T) {
+	parent := NewMap(&DefaultMap)
+	parent.AddResolver("sku", NewMemoryResolver("A1"))
+	parent.AddResolver("user", NewMemoryResolver("bob"))
+	child := NewMap(parent)
+	child.AddResolver("user", NewMemoryResolver("alice"))
+
+	order := orderTestType{SKU: "A1", Customer: "alice"}
+	if ok, errs := child.IsValid(order); !ok {
+		t.Fatal("Expected the child to resolve with its own and inherited resolvers", errs)
+	}
+	if ok, errs := parent.IsValid(order); ok || len(errs) != 1 || errs[0].Key != "Customer" {
+		t.Fatal("Expected the parent to keep its own user resolver", errs)
+	}
+}
@@ -134,6 +134,33 @@ func (m *floatValueValidation) Validate(value interface{}, obj reflect.Value) *V
 	return nil
 }
 
+func (m *intValueValidation) limit() (string, interface{}, bool) {
+	return "value", m.value, m.less
+}
+
+// Parameters returns the limit as value.
+func (m *intValueValidation) Parameters() map[string]interface{} {
+	return map[string]interface{}{"value": m.value}
+}
+
+func (m *uintValueValidation) limit() (string, interface{}, bool) {
+	return "value", m.value, m.less
+}
+
+// Parameters returns the limit as value.
+func (m *uintValueValidation) Parameters() map[string]interface{} {
+	return map[string]interface{}{"value": m.value}
+}
+
+func (m *floatValueValidation) limit() (string, interface{}, bool) {
+	return "value", m.value, m.less
+}
+
+// Parameters returns the limit as value.
+func (m *floatValueValidation) Parameters() map[string]interface{} {
+	return map[string]interface{}{"value": m.value}
+}
+
 func newMinValueValidation(options string, kind reflect.Kind) (Interface, error) {
 	switch kind {
 	case reflect.Int:
@@ -145,7 +172,7 @@ func newMinValueValidation(options string, kind reflect.Kind) (Interface, error)
 	case reflect.Int32:
 		fallthrough
 	case reflect.Int64:
-		value, err := strconv.ParseInt(options, 10, 0)
+		value, err := strconv.ParseInt(options, 10, bitSize(kind))
 		if err != nil {
 			return nil, err
 		}
@@ -162,7 +189,7 @@ func newMinValueValidation(options string, kind reflect.Kind) (Interface, error)
 	case reflect.Uint32:
 		fallthrough
 	case reflect.Uint64:
-		value, err := strconv.ParseUint(options, 10, 0)
+		value, err := strconv.ParseUint(options, 10, bitSize(kind))
 		if err != nil {
 			return nil, err
 		}
@@ -173,7 +200,7 @@ func newMinValueValidation(options string, kind reflect.Kind) (Interface, error)
 	case reflect.Float32:
 		fallthrough
 	case reflect.Float64:
-		value, err := strconv.ParseFloat(options, 64)
+		value, err := strconv.ParseFloat(options, bitSize(kind))
 		if err != nil {
 			return nil, err
 		}
@@ -200,7 +227,7 @@ func newMaxValueValidation(options string, kind reflect.Kind) (Interface, error)
 	case reflect.Int32:
 		fallthrough
 	case reflect.Int64:
-		value, err := strconv.ParseInt(options, 10, 0)
+		value, err := strconv.ParseInt(options, 10, bitSize(kind))
 		if err != nil {
 			return nil, err
 		}
@@ -217,7 +244,7 @@ func newMaxValueValidation(options string, kind reflect.Kind) (Interface, error)
 	case reflect.Uint32:
 		fallthrough
 	case reflect.Uint64:
-		value, err := strconv.ParseUint(options, 10, 0)
+		value, err := strconv.ParseUint(options, 10, bitSize(kind))
 		if err != nil {
 			return nil, err
 		}
@@ -228,7 +255,7 @@ func newMaxValueValidation(options string, kind reflect.Kind) (Interface, error)
 	case reflect.Float32:
 		fallthrough
 	case reflect.Float64:
-		value, err := strconv.ParseFloat(options, 64)
+		value, err := strconv.ParseFloat(options, bitSize(kind))
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,41 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+)
+
+// OpenAPIComponents is the components object of an OpenAPI 3.1 document,
+// whose schemas are JSON Schema (draft 2020-12) schemas.
+type OpenAPIComponents struct {
+	Schemas map[string]*JSONSchema `json:"schemas"`
+}
+
+// ExportOpenAPI generates the OpenAPI component schemas of types using
+// DefaultValidationMap. See Map.ExportOpenAPI.
+func ExportOpenAPI(types ...reflect.Type) (*OpenAPIComponents, error) {
+	return DefaultMap.ExportOpenAPI(types...)
+}
+
+// ExportOpenAPI generates the OpenAPI 3.1 component schemas of the struct
+// types, or the struct types they point to, and of the named structs their
+// fields hold, keyed by type name. The schemas are those of ExportJSONSchema,
+// referencing each other under #/components/schemas/. In addition, the
+// properties without omitempty which are not pointers are listed as
+// required, since encoding/json always encodes them.
+func (vm *Map) ExportOpenAPI(types ...reflect.Type) (*OpenAPIComponents, error) {
+	e := newSchemaExporter(vm, "#/components/schemas/")
+	e.required = true
+	for _, objectType := range types {
+		for objectType != nil && objectType.Kind() == reflect.Ptr {
+			objectType = objectType.Elem()
+		}
+		if objectType == nil || objectType.Kind() != reflect.Struct || objectType.Name() == "" {
+			return nil, errors.New("validation: ExportOpenAPI requires named struct types")
+		}
+		if _, err := e.ref(objectType); err != nil {
+			return nil, err
+		}
+	}
+	return &OpenAPIComponents{Schemas: e.defs}, nil
+}
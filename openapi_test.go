@@ -0,0 +1,68 @@
+package validation
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type openAPITestStatus string
+
+func (openAPITestStatus) Enum() []interface{} {
+	return []interface{}{"open", "closed"}
+}
+
+type openAPITestTicket struct {
+	Title    string              `json:"title" validation:"min_length=1"`
+	Status   openAPITestStatus   `json:"status"`
+	Priority *int                `json:"priority"`
+	Notes    string              `json:"notes,omitempty"`
+	Customer *schemaTestAddress  `json:"customer"`
+	Related  []openAPITestTicket `json:"related,omitempty"`
+}
+
+func TestExportOpenAPI(t *testing.T) {
+	components, err := ExportOpenAPI(reflect.TypeOf(openAPITestTicket{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := json.Marshal(components)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"schemas": {
+		"openAPITestTicket": {
+			"type": "object",
+			"properties": {
+				"title": {"type": "string", "minLength": 1},
+				"status": {"type": "string", "enum": ["open", "closed"]},
+				"priority": {"type": "integer"},
+				"notes": {"type": "string"},
+				"customer": {"$ref": "#/components/schemas/schemaTestAddress"},
+				"related": {"type": "array", "items": {"$ref": "#/components/schemas/openAPITestTicket"}}
+			},
+			"required": ["title", "status"]
+		},
+		"schemaTestAddress": {
+			"type": "object",
+			"properties": {
+				"street": {"type": "string", "minLength": 2, "maxLength": 40},
+				"zip": {"type": "string", "pattern": "^[0-9]{5}$"}
+			},
+			"required": ["street"]
+		}
+	}}`
+	var a, e interface{}
+	json.Unmarshal(actual, &a)
+	json.Unmarshal([]byte(expected), &e)
+	if !reflect.DeepEqual(a, e) {
+		t.Fatalf("Unexpected components:\n%s", actual)
+	}
+}
+
+func TestExportOpenAPIErrors(t *testing.T) {
+	if _, err := ExportOpenAPI(reflect.TypeOf(struct{ A int }{})); err == nil {
+		t.Fatal("Expected an error for an anonymous struct")
+	}
+}
@@ -0,0 +1,57 @@
+package validation
+
+import (
+	"context"
+	"reflect"
+)
+
+// Options control how IsValidWithOptions validates an object. The zero value
+// runs every validation and reports all errors, like IsValid.
+type Options struct {
+	// Context is passed to validations implementing ContextValidator and
+	// stops validation once it is done. context.Background() is used if nil.
+	Context context.Context
+
+	// FailFast stops validation at the first error.
+	FailFast bool
+
+	// Bail stops validating a field at its first failing rule, so e.g. a
+	// failing min_length suppresses the format error of the same field.
+	Bail bool
+
+	// MaxErrors stops validation once this many errors have been found. Zero
+	// means no limit.
+	MaxErrors int
+
+	// Nested validates the structs held by the fields of the object too,
+	// directly or through pointers, interfaces, slices and arrays, with
+	// errors keyed like "Address.Street" or "Items[2].Price". Nil pointers
+	// among them are skipped and cycles of pointers are followed once.
+	Nested bool
+
+	// Workers is the number of goroutines IsValidAll validates elements with.
+	// runtime.GOMAXPROCS(0) is used if it is not positive.
+	Workers int
+}
+
+// IsValidWithOptions determines if an object is valid based on its
+// validation tags using DefaultValidationMap. See Map.IsValidWithOptions.
+func IsValidWithOptions(object interface{}, opts Options) (bool, []ValidationError) {
+	return DefaultMap.IsValidWithOptions(object, opts)
+}
+
+// IsValidWithOptions determines if an object is valid based on its
+// validation tags, stopping early as requested by opts.
+//
+// object may be a struct, a slice or array of structs, whose elements are
+// validated with errors keyed like "[2].Name", or a pointer or interface
+// holding one of these. Structs held by its fields are only validated if
+// opts.Nested is set. A nil object or one of another kind is invalid with an
+// error keyed by "object".
+func (vm *Map) IsValidWithOptions(object interface{}, opts Options) (bool, []ValidationError) {
+	r := newRun(vm, opts)
+	if r.validateValue(reflect.ValueOf(object), "", true) {
+		r.resolve()
+	}
+	return len(r.errors) == 0, r.errors
+}
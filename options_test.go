@@ -0,0 +1,52 @@
+package validation
+
+import "testing"
+
+type optionsTestType struct {
+	Code  string `validation:"min_length=3 format=regexp:^[A-Z]+$"`
+	Name  string `validation:"min_length=1"`
+	Count int    `validation:"min=1"`
+}
+
+func TestIsValidWithOptionsDefault(t *testing.T) {
+	ok, errs := IsValidWithOptions(optionsTestType{}, Options{})
+	if ok || len(errs) != 4 {
+		t.Fatal("Expected every error to be reported", errs)
+	}
+}
+
+func TestIsValidWithOptionsFailFast(t *testing.T) {
+	ok, errs := IsValidWithOptions(optionsTestType{}, Options{FailFast: true})
+	if ok || len(errs) != 1 {
+		t.Fatal("Expected a single error", errs)
+	}
+	if errs[0].Key != "Count" {
+		t.Fatal("Expected the first validated field to fail", errs)
+	}
+}
+
+func TestIsValidWithOptionsBail(t *testing.T) {
+	ok, errs := IsValidWithOptions(optionsTestType{Code: "a"}, Options{Bail: true})
+	if ok || len(errs) != 3 {
+		t.Fatal("Expected one error per field", errs)
+	}
+	for _, err := range errs {
+		if err.Key == "Code" && err.Message != "must be at least 3 characters" {
+			t.Fatal("Format error should have been suppressed by min_length", err)
+		}
+	}
+}
+
+func TestIsValidWithOptionsMaxErrors(t *testing.T) {
+	ok, errs := IsValidWithOptions(optionsTestType{}, Options{MaxErrors: 2})
+	if ok || len(errs) != 2 {
+		t.Fatal("Expected errors to be capped at 2", errs)
+	}
+}
+
+func TestIsValidWithOptionsPointer(t *testing.T) {
+	ok, errs := IsValidWithOptions(&optionsTestType{Code: "ABC", Name: "a", Count: 1}, Options{FailFast: true})
+	if !ok {
+		t.Fatal("Expected pointer to a valid object to be valid", errs)
+	}
+}
@@ -0,0 +1,288 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"strconv"
+)
+
+// RuleError describes a problem in a rule file. Line and Column locate the
+// problem, starting at 1; Type and Field name the type and field it
+// concerns, if any.
+type RuleError struct {
+	File         string
+	Line, Column int
+	Type, Field  string
+	Err          error
+}
+
+func (e *RuleError) Error() string {
+	msg := "validation: "
+	if e.File != "" {
+		msg += e.File + ":"
+	}
+	msg += strconv.Itoa(e.Line) + ":" + strconv.Itoa(e.Column) + ": "
+	if e.Type != "" {
+		msg += e.Type
+		if e.Field != "" {
+			msg += "." + e.Field
+		}
+		msg += ": "
+	}
+	return msg + e.Err.Error()
+}
+
+// RegisterType makes objectType known to rule files as name using
+// DefaultValidationMap. See Map.RegisterType.
+func RegisterType(name string, objectType reflect.Type) {
+	DefaultMap.RegisterType(name, objectType)
+}
+
+// RegisterType makes the struct type objectType, or the struct type it
+// points to, known to the rule files loaded into vm, and into the Maps
+// created by NewMap with vm as parent, as name. It panics if objectType is
+// not a struct type.
+func (vm *Map) RegisterType(name string, objectType reflect.Type) {
+	for objectType != nil && objectType.Kind() == reflect.Ptr {
+		objectType = objectType.Elem()
+	}
+	if objectType == nil || objectType.Kind() != reflect.Struct {
+		panic("validation: RegisterType requires a struct type")
+	}
+	vm.types.Store(name, objectType)
+}
+
+// registeredType returns the type registered as name on vm or, failing
+// that, on the closest of its parents.
+func (vm *Map) registeredType(name string) (reflect.Type, bool) {
+	for m := vm; m != nil; m = m.parent {
+		if objectType, ok := m.types.Load(name); ok {
+			return objectType.(reflect.Type), true
+		}
+	}
+	return nil, false
+}
+
+// LoadRules loads rules into DefaultValidationMap. See Map.LoadRules.
+func LoadRules(data []byte) error {
+	return DefaultMap.LoadRules(data)
+}
+
+// LoadRules sets the rules in data, a JSON object mapping the names of types
+// registered with RegisterType to objects mapping field names to rules in
+// the format of the validation tags, e.g.
+//
+//	{
+//		"Customer": {"Name": "min_length=1 max_length=20", "Notes": ""}
+//	}
+//
+// The rules of every type in data replace those set for it before, as
+// SetRules does. Either all types in data get their new rules or, if a
+// *RuleError is returned, none.
+func (vm *Map) LoadRules(data []byte) error {
+	_, err := vm.loadRules("", data, nil)
+	return err
+}
+
+// LoadRuleFile loads the rule file name into DefaultValidationMap. See
+// Map.LoadRuleFile.
+func LoadRuleFile(name string) error {
+	return DefaultMap.LoadRuleFile(name)
+}
+
+// LoadRuleFile loads the rules of the file name into vm like LoadRules.
+func (vm *Map) LoadRuleFile(name string) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	_, err = vm.loadRules(name, data, nil)
+	return err
+}
+
+// ruleFileEntry is a type in a rule file.
+type ruleFileEntry struct {
+	name   string
+	offset int64
+	fields []ruleFileField
+}
+
+// ruleFileField is a field of a type in a rule file.
+type ruleFileField struct {
+	name, rules string
+	offset      int64
+}
+
+// loadRules sets the rules of data, read from file, and returns the types
+// they were set for. The rules of the types in previous which data does not
+// list are dropped.
+func (vm *Map) loadRules(file string, data []byte, previous []reflect.Type) ([]reflect.Type, error) {
+	fail := func(offset int64, typeName, field string, err error) error {
+		line, column := position(data, offset)
+		return &RuleError{File: file, Line: line, Column: column, Type: typeName, Field: field, Err: err}
+	}
+
+	entries, err := parseRuleFile(data)
+	if err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			// The offset follows the invalid character.
+			return nil, fail(syntaxErr.Offset-1, "", "", err)
+		}
+		var ruleErr *RuleError
+		if errors.As(err, &ruleErr) {
+			ruleErr.File = file
+			return nil, ruleErr
+		}
+		return nil, fail(int64(len(data)), "", "", err)
+	}
+
+	changes := ruleSet{}
+	var types []reflect.Type
+	names := map[reflect.Type]string{}
+	offsets := map[[2]string]int64{}
+	for _, entry := range entries {
+		objectType, ok := vm.registeredType(entry.name)
+		if !ok {
+			return nil, fail(entry.offset, entry.name, "", errors.New("is not a registered type"))
+		}
+		if _, ok := changes[objectType]; ok {
+			return nil, fail(entry.offset, entry.name, "", errors.New("is listed more than once"))
+		}
+		tr := &typeRules{fields: map[string][]Rule{}}
+		for _, field := range entry.fields {
+			var rules []Rule
+			if field.rules != "" {
+				if rules, err = ParseTag(field.rules); err != nil {
+					return nil, fail(field.offset, entry.name, field.name, err)
+				}
+			}
+			tr.fields[field.name] = rules
+			offsets[[2]string{entry.name, field.name}] = field.offset
+		}
+		changes[objectType] = tr
+		names[objectType] = entry.name
+		offsets[[2]string{entry.name, ""}] = entry.offset
+		types = append(types, objectType)
+	}
+	for _, objectType := range previous {
+		if _, ok := changes[objectType]; !ok {
+			changes[objectType] = nil
+		}
+	}
+
+	if err := vm.updateRules(changes); err != nil {
+		var compileErr *CompileError
+		if !errors.As(err, &compileErr) {
+			return nil, err
+		}
+		name := names[compileErr.Type]
+		offset, ok := offsets[[2]string{name, compileErr.Field}]
+		if !ok {
+			offset = offsets[[2]string{name, ""}]
+		}
+		return nil, fail(offset, name, compileErr.Field, compileErr.Err)
+	}
+	return types, nil
+}
+
+// parseRuleFile parses the types of a rule file, recording the offsets of
+// their names and fields.
+func parseRuleFile(data []byte) ([]ruleFileEntry, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	offset := func() int64 {
+		return tokenStart(data, decoder.InputOffset())
+	}
+	fail := func(offset int64, typeName, field, message string) error {
+		line, column := position(data, offset)
+		return &RuleError{Line: line, Column: column, Type: typeName, Field: field, Err: errors.New(message)}
+	}
+	expectDelim := func(delim json.Delim, typeName, message string) error {
+		at := offset()
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		if token != delim {
+			return fail(at, typeName, "", message)
+		}
+		return nil
+	}
+
+	if err := expectDelim('{', "", "rules must be an object of types"); err != nil {
+		return nil, err
+	}
+	var entries []ruleFileEntry
+	for decoder.More() {
+		entry := ruleFileEntry{offset: offset()}
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		entry.name = token.(string)
+		if err := expectDelim('{', entry.name, "must be an object of field rules"); err != nil {
+			return nil, err
+		}
+		seen := map[string]bool{}
+		for decoder.More() {
+			field := ruleFileField{offset: offset()}
+			if token, err = decoder.Token(); err != nil {
+				return nil, err
+			}
+			field.name = token.(string)
+			if seen[field.name] {
+				return nil, fail(field.offset, entry.name, field.name, "is listed more than once")
+			}
+			seen[field.name] = true
+
+			valueOffset := offset()
+			if token, err = decoder.Token(); err != nil {
+				return nil, err
+			}
+			rules, ok := token.(string)
+			if !ok {
+				return nil, fail(valueOffset, entry.name, field.name, "must be a string of rules")
+			}
+			field.rules = rules
+			entry.fields = append(entry.fields, field)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	if at := offset(); at < int64(len(data)) {
+		return nil, fail(at, "", "", "unexpected data after the rules")
+	}
+	return entries, nil
+}
+
+// tokenStart returns the offset of the token following offset in data,
+// skipping white space and separators.
+func tokenStart(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) {
+		switch data[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// position returns the line and column of offset in data.
+func position(data []byte, offset int64) (line, column int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	line = 1 + bytes.Count(data[:offset], []byte("\n"))
+	column = int(offset) - bytes.LastIndexByte(data[:offset], '\n')
+	return line, column
+}
@@ -0,0 +1,90 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func ruleFileTestMap() *Map {
+	vm := NewMap(&DefaultMap)
+	vm.RegisterType("Customer", reflect.TypeOf(&ruleSetTestType{}))
+	vm.RegisterType("Address", reflect.TypeOf(schemaTestAddress{}))
+	return vm
+}
+
+func TestLoadRules(t *testing.T) {
+	vm := ruleFileTestMap()
+	err := vm.LoadRules([]byte(`{
+		"Customer": {"Name": "max_length=3", "Email": "", "Count": "min=1"},
+		"Address": {"Street": "min_length=5"}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	customer := ruleSetTestType{rulesTestBase: rulesTestBase{ID: 1}, Name: "abcdef", Email: "a"}
+	if ok, errs := vm.IsValid(customer); ok || len(errs) != 2 || errs[0].Key != "Count" || errs[1].Key != "Name" {
+		t.Fatal("Expected errors of the loaded rules", errs)
+	}
+	if ok, errs := vm.IsValid(schemaTestAddress{Street: "Main", Zip: "12345"}); ok || errs[0].Key != "Street" {
+		t.Fatal("Expected errors of the loaded rules", errs)
+	}
+
+	child := NewMap(vm)
+	if err := child.LoadRules([]byte(`{"Address": {"Street": "min_length=1"}}`)); err != nil {
+		t.Fatal("Expected registered types to be inherited", err)
+	}
+}
+
+func TestLoadRulesErrors(t *testing.T) {
+	invalid := []struct {
+		src, message string
+	}{
+		{`{"Customer": {"Name": "max_length=3"}`, "1:37: unexpected end of JSON input"},
+		{`[]`, "1:1: rules must be an object of types"},
+		{`{"Customer": []}`, "1:14: Customer: must be an object of field rules"},
+		{"{\n  \"Order\": {}\n}", "2:3: Order: is not a registered type"},
+		{"{\n  \"Customer\": {\n    \"Phone\": \"min_length=1\"\n  }\n}", "3:5: Customer.Phone: is not a field of validation.ruleSetTestType"},
+		{"{\"Customer\": {\"Name\": 3}}", "1:23: Customer.Name: must be a string of rules"},
+		{"{\"Customer\": {\"Name\": \"max_length\"}}", "1:15: Customer.Name: max_length is not of the form name=options"},
+		{"{\"Customer\": {\"Name\": \"phone=us\"}}", "1:15: Customer.Name: phone is not a known validation"},
+		{"{\"Customer\": {\"ID\": \"min=-1\"}}", "1:15: Customer.ID: min=-1 strconv.ParseUint"},
+		{"{\"Customer\": {\"Name\": \"min_length=1\", \"Name\": \"\"}}", "1:39: Customer.Name: is listed more than once"},
+		{"{\"Customer\": {}, \"Customer\": {}}", "1:18: Customer: is listed more than once"},
+		{"{} {}", "1:4: unexpected data after the rules"},
+		{"{\"Customer\" {}}", "1:13: invalid character '{' after object key"},
+	}
+	for _, test := range invalid {
+		vm := ruleFileTestMap()
+		err := vm.LoadRules([]byte(test.src))
+		if _, ok := err.(*RuleError); !ok || !strings.Contains(err.Error(), test.message) {
+			t.Errorf("%s: expected a *RuleError with %q, got %v", test.src, test.message, err)
+		}
+	}
+
+	vm := ruleFileTestMap()
+	err := vm.LoadRules([]byte(`{"Address": {"Street": "min_length=5"}, "Customer": {"Phone": ""}}`))
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if ok, errs := vm.IsValid(schemaTestAddress{Street: "Main", Zip: "12345"}); !ok {
+		t.Fatal("Expected no rules to be loaded from an invalid file", errs)
+	}
+}
+
+func TestLoadRuleFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(name, []byte("{\n\t\"Address\": {\"Zip\": \"min\"}\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := ruleFileTestMap().LoadRuleFile(name)
+	if err == nil || !strings.HasPrefix(err.Error(), "validation: "+name+":2:14: Address.Zip: ") {
+		t.Fatal("Expected the error to name the file", err)
+	}
+	if err := ruleFileTestMap().LoadRuleFile(name + ".missing"); !os.IsNotExist(err) {
+		t.Fatal("Expected a not exist error", err)
+	}
+}
@@ -0,0 +1,80 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+)
+
+// Parameterized can optionally be implemented by a validation to describe
+// the options it was built with, e.g. the limit of min=3, for Map.Rules.
+type Parameterized interface {
+	Parameters() map[string]interface{}
+}
+
+// RuleDescriptor describes a validation compiled for a field of a struct
+// type.
+type RuleDescriptor struct {
+	// Path is the name of the field as used in the keys of its errors.
+	// Fields promoted from embedded structs use their promoted name. Index
+	// is the index sequence of the field for reflect.Value.FieldByIndex.
+	Path  string
+	Index []int
+
+	// Name and Options are the parts of the rule in the validation tag.
+	Name    string
+	Options string
+
+	// Params holds the parsed options of validations implementing
+	// Parameterized and is nil for others.
+	Params map[string]interface{}
+
+	// Type is the type of the field.
+	Type reflect.Type
+}
+
+// Rules describes the validations of objectType using DefaultValidationMap.
+// See Map.Rules.
+func Rules(objectType reflect.Type) ([]RuleDescriptor, error) {
+	return DefaultMap.Rules(objectType)
+}
+
+// Rules describes the validations compiled for the struct type objectType,
+// or the struct type it points to, in the order the fields are declared and
+// the rules appear in their tags. Structs held by its fields are described
+// by their own types. A *CompileError is returned for invalid validation
+// tags.
+func (vm *Map) Rules(objectType reflect.Type) ([]RuleDescriptor, error) {
+	for objectType != nil && objectType.Kind() == reflect.Ptr {
+		objectType = objectType.Elem()
+	}
+	if objectType == nil || objectType.Kind() != reflect.Struct {
+		return nil, errors.New("validation: Rules requires a struct type")
+	}
+	compiled, err := vm.load(objectType)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]compiledRule, len(compiled.rules))
+	copy(rules, compiled.rules)
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].field < rules[j].field
+	})
+
+	descriptors := make([]RuleDescriptor, 0, len(rules))
+	for _, rule := range rules {
+		descriptor := RuleDescriptor{
+			Path:    rule.FieldName(),
+			Index:   append([]int(nil), rule.index...),
+			Name:    rule.rule.Name,
+			Options: rule.rule.Options,
+			Type:    objectType.FieldByIndex(rule.index).Type,
+		}
+		if p, ok := rule.Interface.(Parameterized); ok {
+			descriptor.Params = p.Parameters()
+		}
+		descriptors = append(descriptors, descriptor)
+	}
+	return descriptors, nil
+}
@@ -0,0 +1,60 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+)
+
+type rulesTestBase struct {
+	ID uint `validation:"min=1"`
+}
+
+type rulesTestType struct {
+	rulesTestBase
+	Name    string  `validation:"min_length=1 max_length=20"`
+	Email   string  `validation:"format=email"`
+	Code    string  `validation:"format=regexp:^[A-Z]+$"`
+	Score   float64 `validation:"max=10"`
+	SKU     string  `validation:"lookup=sku"`
+	Custom  int     `validation:"reject=yes"`
+	Ignored string
+}
+
+func TestRules(t *testing.T) {
+	vm := NewMap(&DefaultMap)
+	vm.AddValidation("reject", func(options string, kind reflect.Kind) (Interface, error) {
+		return &rejectValidation{}, nil
+	})
+
+	rules, err := vm.Rules(reflect.TypeOf(&rulesTestType{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []RuleDescriptor{
+		{Path: "ID", Index: []int{0, 0}, Name: "min", Options: "1", Params: map[string]interface{}{"value": uint64(1)}, Type: reflect.TypeOf(uint(0))},
+		{Path: "Name", Index: []int{1}, Name: "min_length", Options: "1", Params: map[string]interface{}{"length": 1}, Type: reflect.TypeOf("")},
+		{Path: "Name", Index: []int{1}, Name: "max_length", Options: "20", Params: map[string]interface{}{"length": 20}, Type: reflect.TypeOf("")},
+		{Path: "Email", Index: []int{2}, Name: "format", Options: "email", Params: map[string]interface{}{"format": "email", "pattern": EmailPattern}, Type: reflect.TypeOf("")},
+		{Path: "Code", Index: []int{3}, Name: "format", Options: "regexp:^[A-Z]+$", Params: map[string]interface{}{"pattern": "^[A-Z]+$"}, Type: reflect.TypeOf("")},
+		{Path: "Score", Index: []int{4}, Name: "max", Options: "10", Params: map[string]interface{}{"value": float64(10)}, Type: reflect.TypeOf(0.0)},
+		{Path: "SKU", Index: []int{5}, Name: "lookup", Options: "sku", Params: map[string]interface{}{"resolver": "sku"}, Type: reflect.TypeOf("")},
+		{Path: "Custom", Index: []int{6}, Name: "reject", Options: "yes", Type: reflect.TypeOf(0)},
+	}
+	if !reflect.DeepEqual(rules, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, rules)
+	}
+}
+
+func TestRulesErrors(t *testing.T) {
+	if _, err := Rules(reflect.TypeOf(0)); err == nil {
+		t.Fatal("Expected an error for a non-struct type")
+	}
+	type invalid struct {
+		A int `validation:"min=abc"`
+	}
+	if _, err := Rules(reflect.TypeOf(invalid{})); err == nil {
+		t.Fatal("Expected an error for an invalid tag")
+	} else if _, ok := err.(*CompileError); !ok {
+		t.Fatal("Expected a *CompileError", err)
+	}
+}
@@ -0,0 +1,152 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+)
+
+// typeRules holds the rules replacing the validation tags of the fields of a
+// type, by field name. It is never modified once stored in a ruleSet.
+type typeRules struct {
+	fields map[string][]Rule
+}
+
+// field returns the rules replacing the tag of field, if any.
+func (tr *typeRules) field(field reflect.StructField) ([]Rule, bool) {
+	if tr == nil || field.Anonymous {
+		return nil, false
+	}
+	rules, ok := tr.fields[field.Name]
+	return rules, ok
+}
+
+// ruleSet holds the rules set on a Map by type. A ruleSet is replaced as a
+// whole when rules change, so a validation never sees a partial update.
+type ruleSet map[reflect.Type]*typeRules
+
+// SetRules replaces the validation tags of fields of objectType, or the
+// struct type it points to, using DefaultValidationMap. See Map.SetRules.
+func SetRules(objectType reflect.Type, rules map[string]string) error {
+	return DefaultMap.SetRules(objectType, rules)
+}
+
+// SetRules replaces the validation tags of fields of the struct type
+// objectType, or the struct type it points to, with rules, which maps field
+// names, as used in the keys of errors, to rules in the format of the tags.
+// An empty rule removes the validations of a field; fields not in rules keep
+// their tags. The rules previously set for objectType are dropped, all of
+// them if rules is nil. Maps created by NewMap with vm as parent use the
+// rules unless they set their own for objectType.
+//
+// The new rules are compiled before they are used, so validations go on with
+// the previous rules if a *CompileError is returned.
+func (vm *Map) SetRules(objectType reflect.Type, rules map[string]string) error {
+	for objectType != nil && objectType.Kind() == reflect.Ptr {
+		objectType = objectType.Elem()
+	}
+	if objectType == nil || objectType.Kind() != reflect.Struct {
+		return errors.New("validation: SetRules requires a struct type")
+	}
+	if rules == nil {
+		return vm.updateRules(ruleSet{objectType: nil})
+	}
+
+	tr := &typeRules{fields: map[string][]Rule{}}
+	for name, tag := range rules {
+		var fieldRules []Rule
+		if tag != "" {
+			var err error
+			if fieldRules, err = ParseTag(tag); err != nil {
+				return &CompileError{Type: objectType, Field: name, Err: err}
+			}
+		}
+		tr.fields[name] = fieldRules
+	}
+	return vm.updateRules(ruleSet{objectType: tr})
+}
+
+// typeRules returns the rules set for objectType on vm or, failing that, on
+// the closest of its parents.
+func (vm *Map) typeRules(objectType reflect.Type) *typeRules {
+	for m := vm; m != nil; m = m.parent {
+		if set := m.rules.Load(); set != nil {
+			if tr, ok := (*set)[objectType]; ok {
+				return tr
+			}
+		}
+	}
+	return nil
+}
+
+// updateRules replaces the rules of the types in changes, dropping those of
+// types mapped to nil. The types are compiled with their new rules first and
+// nothing is changed if any of them fails to compile.
+func (vm *Map) updateRules(changes ruleSet) error {
+	vm.rulesMu.Lock()
+	defer vm.rulesMu.Unlock()
+
+	compiled := map[reflect.Type]*compiledType{}
+	for objectType, tr := range changes {
+		if tr != nil {
+			if err := checkRuleFields(objectType, tr); err != nil {
+				return err
+			}
+		}
+		effective := tr
+		if tr == nil && vm.parent != nil {
+			effective = vm.parent.typeRules(objectType)
+		}
+		c, err := vm.compileWith(objectType, effective)
+		if err != nil {
+			return err
+		}
+		compiled[objectType] = c
+	}
+
+	next := ruleSet{}
+	if current := vm.rules.Load(); current != nil {
+		for objectType, tr := range *current {
+			next[objectType] = tr
+		}
+	}
+	for objectType, tr := range changes {
+		if tr == nil {
+			delete(next, objectType)
+		} else {
+			next[objectType] = tr
+		}
+	}
+	vm.rules.Store(&next)
+	vm.version.Add(1)
+
+	for objectType, c := range compiled {
+		// A concurrent SetRules on a parent may have changed the rules in
+		// use since.
+		if c.overrides == vm.typeRules(objectType) {
+			vm.set(objectType, c)
+		} else {
+			vm.Forget(objectType)
+		}
+	}
+	return nil
+}
+
+// checkRuleFields reports fields of tr which objectType does not have.
+func checkRuleFields(objectType reflect.Type, tr *typeRules) error {
+	names := map[string]bool{}
+	for _, field := range reflect.VisibleFields(objectType) {
+		if !field.Anonymous {
+			names[field.Name] = true
+		}
+	}
+	for name := range tr.fields {
+		if !names[name] {
+			return &CompileError{
+				Type:  objectType,
+				Field: name,
+				Err:   errors.New("is not a field of " + objectType.String()),
+			}
+		}
+	}
+	return nil
+}
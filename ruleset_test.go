@@ -0,0 +1,79 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+)
+
+type ruleSetTestType struct {
+	rulesTestBase
+	Name  string `validation:"min_length=1"`
+	Email string `validation:"format=email"`
+	Count int
+}
+
+func TestSetRules(t *testing.T) {
+	vm := NewMap(&DefaultMap)
+	typ := reflect.TypeOf(ruleSetTestType{})
+	value := ruleSetTestType{rulesTestBase: rulesTestBase{ID: 1}, Name: "abcdef", Email: "a", Count: 0}
+
+	if ok, errs := vm.IsValid(value); ok || len(errs) != 1 || errs[0].Key != "Email" {
+		t.Fatal("Expected an Email error from the tags", errs)
+	}
+
+	err := vm.SetRules(reflect.PtrTo(typ), map[string]string{"Name": "max_length=3", "Email": "", "Count": "min=1", "ID": "min=2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, errs := vm.IsValid(value)
+	if ok || len(errs) != 3 || errs[0].Key != "Count" || errs[1].Key != "Name" || errs[2].Key != "ID" {
+		t.Fatal("Expected errors from the rules set", errs)
+	}
+	if ok, _ := DefaultMap.IsValid(value); ok {
+		t.Fatal("Expected the rules not to affect the parent")
+	}
+
+	child := NewMap(vm)
+	if ok, errs := child.IsValid(value); ok || len(errs) != 3 {
+		t.Fatal("Expected the rules to be inherited", errs)
+	}
+
+	if err := vm.SetRules(typ, nil); err != nil {
+		t.Fatal(err)
+	}
+	if ok, errs := vm.IsValid(value); ok || len(errs) != 1 || errs[0].Key != "Email" {
+		t.Fatal("Expected the tags to be used again", errs)
+	}
+	if ok, errs := child.IsValid(value); ok || len(errs) != 1 || errs[0].Key != "Email" {
+		t.Fatal("Expected the child to use the tags again", errs)
+	}
+}
+
+func TestSetRulesErrors(t *testing.T) {
+	vm := NewMap(&DefaultMap)
+	typ := reflect.TypeOf(ruleSetTestType{})
+	if err := vm.SetRules(typ, map[string]string{"Name": "max_length=3"}); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := map[string]map[string]string{
+		"unknown field":      {"Phone": "min_length=1"},
+		"embedded field":     {"rulesTestBase": "min=1"},
+		"malformed rule":     {"Name": "max_length"},
+		"unknown validation": {"Name": "phone=us"},
+		"out of range":       {"ID": "min=-1"},
+	}
+	for name, rules := range invalid {
+		err := vm.SetRules(typ, rules)
+		if _, ok := err.(*CompileError); !ok {
+			t.Errorf("%s: expected a *CompileError, got %v", name, err)
+		}
+	}
+	if err := vm.SetRules(reflect.TypeOf(0), map[string]string{}); err == nil {
+		t.Fatal("Expected an error for a non-struct type")
+	}
+
+	if ok, errs := vm.IsValid(ruleSetTestType{rulesTestBase: rulesTestBase{ID: 1}, Name: "abcd", Email: "a@b.co"}); ok || len(errs) != 1 || errs[0].Key != "Name" {
+		t.Fatal("Expected the previous rules to stay in use", errs)
+	}
+}
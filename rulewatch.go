@@ -0,0 +1,116 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// RuleFile is a rule file loaded into a Map, which can be loaded again when
+// it changes. Every load replaces the rules of the previous one as a whole:
+// types no longer listed get their validation tags back. A load that fails
+// leaves the rules, and the validations compiled from them, as they were.
+type RuleFile struct {
+	vm   *Map
+	name string
+
+	mu      sync.Mutex
+	types   []reflect.Type
+	modTime time.Time
+	size    int64
+}
+
+// OpenRuleFile loads the rule file name into DefaultValidationMap. See
+// Map.OpenRuleFile.
+func OpenRuleFile(name string) (*RuleFile, error) {
+	return DefaultMap.OpenRuleFile(name)
+}
+
+// OpenRuleFile loads the rule file name into vm like LoadRuleFile and
+// returns it for reloading.
+func (vm *Map) OpenRuleFile(name string) (*RuleFile, error) {
+	f := &RuleFile{vm: vm, name: name}
+	if err := f.Reload(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Name returns the name of the file.
+func (f *RuleFile) Name() string {
+	return f.name
+}
+
+// Reload loads the file again.
+func (f *RuleFile) Reload() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	info, err := os.Stat(f.name)
+	if err != nil {
+		return err
+	}
+	return f.reloadLocked(info)
+}
+
+// reloadLocked loads the file, whose state is info. f.mu must be held.
+func (f *RuleFile) reloadLocked(info os.FileInfo) error {
+	// The file is recorded as seen even if it fails to load, so Watch
+	// reports each broken version once.
+	f.modTime, f.size = info.ModTime(), info.Size()
+	data, err := os.ReadFile(f.name)
+	if err != nil {
+		return err
+	}
+	types, err := f.vm.loadRules(f.name, data, f.types)
+	if err != nil {
+		return err
+	}
+	f.types = types
+	return nil
+}
+
+// Watch checks the file for changes of its size or modification time every
+// interval and reloads it when it changed, until ctx is done. Errors of
+// checking or reloading the file are passed to onError, if not nil. Watch
+// blocks, so it is usually run in its own goroutine:
+//
+//	go rules.Watch(ctx, 10*time.Second, func(err error) { log.Println(err) })
+//
+// If interval is not positive, Watch passes an error to onError and returns
+// at once.
+func (f *RuleFile) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	if interval <= 0 {
+		if onError != nil {
+			onError(errors.New("validation: non-positive interval " + interval.String() + " for Watch"))
+		}
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := f.reloadIfChanged(); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+func (f *RuleFile) reloadIfChanged() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	info, err := os.Stat(f.name)
+	if err != nil {
+		return err
+	}
+	if info.Size() == f.size && info.ModTime().Equal(f.modTime) {
+		return nil
+	}
+	return f.reloadLocked(info)
+}
@@ -0,0 +1,125 @@
+package validation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func writeRuleFile(t *testing.T, name, rules string) {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRuleFileReload(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "rules.json")
+	writeRuleFile(t, name, `{"Address": {"Street": "min_length=5"}, "Customer": {"Count": "min=1"}}`)
+
+	vm := ruleFileTestMap()
+	rules, err := vm.OpenRuleFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := schemaTestAddress{Street: "Main", Zip: "12345"}
+	customer := ruleSetTestType{rulesTestBase: rulesTestBase{ID: 1}, Name: "a", Email: "a@b.co"}
+	if ok, _ := vm.IsValid(address); ok {
+		t.Fatal("Expected the rules of the file to be used")
+	}
+
+	writeRuleFile(t, name, `{"Address": {"Street": "min_length=6"}}`)
+	if err := rules.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, errs := vm.IsValid(address); ok || errs[0].Message != "must be at least 6 characters" {
+		t.Fatal("Expected the reloaded rules to be used", errs)
+	}
+	if ok, errs := vm.IsValid(customer); !ok {
+		t.Fatal("Expected the rules of types no longer listed to be dropped", errs)
+	}
+
+	compiled := vm.get(reflect.TypeOf(address))
+	writeRuleFile(t, name, `{"Address": {"Street": "min_length=1", "Phone": "min_length=1"}}`)
+	if err := rules.Reload(); err == nil {
+		t.Fatal("Expected an error for a broken edit")
+	}
+	if vm.get(reflect.TypeOf(address)) != compiled {
+		t.Fatal("Expected the compiled validations to be kept after a broken edit")
+	}
+	if ok, errs := vm.IsValid(address); ok || errs[0].Message != "must be at least 6 characters" {
+		t.Fatal("Expected the previous rules to stay in use", errs)
+	}
+
+	if _, err := vm.OpenRuleFile(name + ".missing"); !os.IsNotExist(err) {
+		t.Fatal("Expected a not exist error", err)
+	}
+}
+
+func TestRuleFileWatch(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "rules.json")
+	writeRuleFile(t, name, `{"Address": {"Street": "min_length=5"}}`)
+	vm := ruleFileTestMap()
+	rules, err := vm.OpenRuleFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 10)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		rules.Watch(ctx, time.Millisecond, func(err error) { errs <- err })
+	}()
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	address := schemaTestAddress{Street: "Main", Zip: "12345"}
+	writeRuleFile(t, name, `{"Address": {"Street": "min_length=1"}}`)
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		if ok, _ := vm.IsValid(address); ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the changed file to be reloaded")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	writeRuleFile(t, name, `{"Address": {"Street": "min_length"}}`)
+	select {
+	case err := <-errs:
+		if _, ok := err.(*RuleError); !ok {
+			t.Fatal("Expected a *RuleError", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the broken file to be reported")
+	}
+	if ok, errs := vm.IsValid(address); !ok {
+		t.Fatal("Expected the previous rules to stay in use", errs)
+	}
+}
+
+func TestRuleFileWatchInterval(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "rules.json")
+	writeRuleFile(t, name, `{"Address": {"Street": "min_length=5"}}`)
+	rules, err := ruleFileTestMap().OpenRuleFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var reported error
+	rules.Watch(context.Background(), 0, func(err error) { reported = err })
+	if reported == nil {
+		t.Fatal("Expected an error for a zero interval")
+	}
+	rules.Watch(context.Background(), -time.Second, nil)
+}
@@ -0,0 +1,390 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Schema validates documents decoded from JSON into interface{} values, i.e.
+// nil, bool, float64 or json.Number, string, []interface{} and
+// map[string]interface{}, with the validations of a Map. Errors are keyed
+// by the path of the invalid value, e.g. "address.street" or "tags[1]", and
+// by "object" for the document itself.
+type Schema struct {
+	vm *Map
+
+	// typ is the type of valid values, empty if any type is valid, and
+	// nullable reports whether null is valid as well. kind is the kind of
+	// scalar values, which rules are built for, and Invalid for others.
+	typ      string
+	kind     reflect.Kind
+	nullable bool
+
+	rules []schemaRule
+
+	// properties and additional validate the properties of objects, which
+	// may not have other properties than those listed if closed.
+	properties []schemaProperty
+	additional *Schema
+	closed     bool
+
+	// record reports whether properties with nil values count as missing,
+	// as for the records of NewSchema.
+	record bool
+
+	// items validates the elements of arrays.
+	items *Schema
+}
+
+type schemaRule struct {
+	Interface
+	rule Rule
+}
+
+type schemaProperty struct {
+	name     string
+	schema   *Schema
+	required bool
+}
+
+// schemaKinds maps the JSON types that rules can be applied to to the kind
+// the rules are built for.
+var schemaKinds = map[string]reflect.Kind{
+	"string":  reflect.String,
+	"integer": reflect.Int64,
+	"number":  reflect.Float64,
+	"boolean": reflect.Bool,
+}
+
+// SchemaField describes a field of the records validated by a Schema
+// created with NewSchema.
+type SchemaField struct {
+	// Name is the key of the field in the records.
+	Name string `json:"name"`
+
+	// Kind is the kind of the values of the field, named like reflect.Kind
+	// names the kinds string, bool, int, int8 to int64, uint, uint8 to
+	// uint64, float32 and float64. Numbers of any Go type, including
+	// json.Number, are valid if the kind can represent them.
+	Kind string `json:"kind"`
+
+	// Rules holds the rules of the field in the format of the validation
+	// tags.
+	Rules string `json:"rules,omitempty"`
+
+	// Required reports whether records must have the field. nil values
+	// count as missing.
+	Required bool `json:"required,omitempty"`
+}
+
+// NewSchema creates a Schema for records with fields using
+// DefaultValidationMap. See Map.NewSchema.
+func NewSchema(fields ...SchemaField) (*Schema, error) {
+	return DefaultMap.NewSchema(fields...)
+}
+
+// NewSchema creates a Schema validating records, i.e.
+// map[string]interface{} values, whose fields are described by fields, with
+// the validations of vm. Records may hold other fields as well. Like the
+// fields of structs, the fields are validated from last to first.
+func (vm *Map) NewSchema(fields ...SchemaField) (*Schema, error) {
+	schema := &Schema{vm: vm, typ: "object", record: true}
+	for i := len(fields) - 1; i >= 0; i-- {
+		field := fields[i]
+		fail := func(err error) error {
+			return fmt.Errorf("validation: schema field %d (%s): %v", i, field.Name, err)
+		}
+		if field.Name == "" {
+			return nil, fail(errors.New("has no name"))
+		}
+		if schema.property(field.Name) != nil {
+			return nil, fail(errors.New("is listed more than once"))
+		}
+		kind, ok := recordKinds[field.Kind]
+		if !ok {
+			return nil, fail(fmt.Errorf("has unknown kind %q", field.Kind))
+		}
+
+		property := &Schema{vm: vm, typ: field.Kind, kind: kind}
+		if field.Rules != "" {
+			rules, err := ParseTag(field.Rules)
+			if err != nil {
+				return nil, fail(err)
+			}
+			validations, err := vm.BuildRules(rules, kind)
+			if err != nil {
+				return nil, fail(err)
+			}
+			for j, validation := range validations {
+				validation.SetFieldName(field.Name)
+				property.rules = append(property.rules, schemaRule{Interface: validation, rule: rules[j]})
+			}
+		}
+		schema.properties = append(schema.properties, schemaProperty{name: field.Name, schema: property, required: field.Required})
+	}
+	return schema, nil
+}
+
+// ParseSchema creates a Schema from a JSON definition using
+// DefaultValidationMap. See Map.ParseSchema.
+func ParseSchema(data []byte) (*Schema, error) {
+	return DefaultMap.ParseSchema(data)
+}
+
+// ParseSchema creates a Schema like NewSchema from data, a JSON array of
+// fields such as
+//
+//	[
+//		{"name": "email", "kind": "string", "rules": "format=email", "required": true},
+//		{"name": "age", "kind": "uint8", "rules": "min=18"}
+//	]
+func (vm *Map) ParseSchema(data []byte) (*Schema, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var fields []SchemaField
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("validation: invalid schema: %v", err)
+	}
+	return vm.NewSchema(fields...)
+}
+
+// property returns the schema of the property name of objects, or nil.
+func (s *Schema) property(name string) *schemaProperty {
+	for i := range s.properties {
+		if s.properties[i].name == name {
+			return &s.properties[i]
+		}
+	}
+	return nil
+}
+
+// Validate determines if document is valid.
+func (s *Schema) Validate(document interface{}) (bool, []ValidationError) {
+	return s.ValidateWithOptions(document, Options{})
+}
+
+// ValidateWithOptions determines if document is valid, stopping early as
+// requested by opts.
+func (s *Schema) ValidateWithOptions(document interface{}, opts Options) (bool, []ValidationError) {
+	r := newRun(s.vm, opts)
+	if r.validateDocument(s, document, "") {
+		r.resolve()
+	}
+	return len(r.errors) == 0, r.errors
+}
+
+// validateDocument validates value, found at path, against s. It returns
+// false if the run was cancelled or stopped because of its options.
+func (r *run) validateDocument(s *Schema, value interface{}, path string) bool {
+	if r.cancelled() {
+		return false
+	}
+	if value == nil {
+		if s.typ == "" || s.nullable {
+			return true
+		}
+		return r.fail(ValidationError{Key: objectKey(path), Message: "must be of type " + s.typ})
+	}
+	value, ok := documentValue(value, s.typ, s.kind)
+	if !ok {
+		return r.fail(ValidationError{Key: objectKey(path), Message: "must be of type " + s.typ})
+	}
+
+	for _, rule := range s.rules {
+		if lv, ok := rule.Interface.(*lookupValidation); ok {
+			r.addLookup(lv.resolver, objectKey(path), value)
+			continue
+		}
+		var err *ValidationError
+		if cv, ok := rule.Interface.(ContextValidator); ok {
+			err = cv.ValidateContext(r.ctx, value, reflect.Value{})
+		} else {
+			err = rule.Validate(value, reflect.Value{})
+		}
+		if err != nil {
+			err.Key = objectKey(path)
+			if !r.fail(*err) {
+				return false
+			}
+			if r.opts.Bail {
+				break
+			}
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		return r.validateProperties(s, value, path)
+	case []interface{}:
+		if s.items == nil {
+			return true
+		}
+		for i, item := range value {
+			if !r.validateDocument(s.items, item, path+"["+strconv.Itoa(i)+"]") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// validateProperties validates the properties of object, found at path,
+// against s: first those s lists, in its order, then the others in the
+// order of their names.
+func (r *run) validateProperties(s *Schema, object map[string]interface{}, path string) bool {
+	for _, property := range s.properties {
+		value, ok := object[property.name]
+		if !ok || value == nil && s.record {
+			if property.required && !r.fail(ValidationError{Key: joinKey(path, property.name), Message: "is required"}) {
+				return false
+			}
+			continue
+		}
+		if !r.validateDocument(property.schema, value, joinKey(path, property.name)) {
+			return false
+		}
+	}
+
+	if !s.closed && s.additional == nil {
+		return true
+	}
+	var names []string
+	for name := range object {
+		if s.property(name) == nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if s.closed {
+			if !r.fail(ValidationError{Key: joinKey(path, name), Message: "is not allowed"}) {
+				return false
+			}
+		} else if !r.validateDocument(s.additional, object[name], joinKey(path, name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// documentValue converts value to the type the rules of kind expect, i.e.
+// int64 for signed, uint64 for unsigned integers and float64 for floats. ok
+// is false if value is not of type typ or cannot be represented by kind.
+func documentValue(value interface{}, typ string, kind reflect.Kind) (converted interface{}, ok bool) {
+	switch kind {
+	case reflect.Invalid:
+	case reflect.String:
+		_, ok = value.(string)
+		return value, ok
+	case reflect.Bool:
+		_, ok = value.(bool)
+		return value, ok
+	default:
+		return numberValue(value, kind)
+	}
+	switch typ {
+	case "":
+		return value, true
+	case "object":
+		_, ok = value.(map[string]interface{})
+	case "array":
+		_, ok = value.([]interface{})
+	}
+	return value, ok
+}
+
+// numberValue converts the number value to int64, uint64 or float64 for
+// the numeric kind, rounding it to float32 precision for float32. ok is false
+// if value is not a number or out of the range of kind.
+func numberValue(value interface{}, kind reflect.Kind) (interface{}, bool) {
+	number := reflect.ValueOf(value)
+	if n, isNumber := value.(json.Number); isNumber {
+		if i, err := n.Int64(); err == nil {
+			number = reflect.ValueOf(i)
+		} else if f, err := n.Float64(); err == nil {
+			number = reflect.ValueOf(f)
+		} else {
+			return value, false
+		}
+	}
+
+	var i int64
+	var u uint64
+	var f float64
+	switch number.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i = number.Int()
+		u, f = uint64(i), float64(i)
+		if i < 0 {
+			u = math.MaxUint64
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u = number.Uint()
+		i, f = int64(u), float64(u)
+		if u > math.MaxInt64 {
+			i = math.MaxInt64
+		}
+	case reflect.Float32, reflect.Float64:
+		f = number.Float()
+		if f != math.Trunc(f) || math.Abs(f) >= 1<<63 {
+			i, u = math.MaxInt64, math.MaxUint64
+		} else {
+			i, u = int64(f), uint64(f)
+			if f < 0 {
+				u = math.MaxUint64
+			}
+		}
+	default:
+		return value, false
+	}
+
+	// Values out of range are replaced by the maximum values above, which
+	// overflow every kind but int64 and uint64.
+	zero := reflect.New(kindTypes[kind]).Elem()
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if float64(i) != f || zero.OverflowInt(i) {
+			return value, false
+		}
+		return i, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if float64(u) != f || zero.OverflowUint(u) {
+			return value, false
+		}
+		return u, true
+	case reflect.Float32, reflect.Float64:
+		if zero.OverflowFloat(f) {
+			return value, false
+		}
+		if kind == reflect.Float32 {
+			// Round like a float32 field, as the limits of the rules are.
+			f = float64(float32(f))
+		}
+		return f, true
+	}
+	return value, false
+}
+
+// kindTypes maps the kinds of record fields to their types, and recordKinds
+// maps the names of the kinds of SchemaField to the kinds.
+var (
+	kindTypes   = map[reflect.Kind]reflect.Type{}
+	recordKinds = map[string]reflect.Kind{}
+)
+
+func init() {
+	for _, value := range []interface{}{
+		"", false, int(0), int8(0), int16(0), int32(0), int64(0),
+		uint(0), uint8(0), uint16(0), uint32(0), uint64(0), float32(0), float64(0),
+	} {
+		kind := reflect.TypeOf(value).Kind()
+		kindTypes[kind] = reflect.TypeOf(value)
+		recordKinds[kind.String()] = kind
+	}
+}
@@ -0,0 +1,121 @@
+package validation
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewSchema(t *testing.T) {
+	schema, err := NewSchema(
+		SchemaField{Name: "email", Kind: "string", Rules: "format=email", Required: true},
+		SchemaField{Name: "age", Kind: "uint8", Rules: "min=18"},
+		SchemaField{Name: "score", Kind: "float32", Rules: "min=0 max=10"},
+		SchemaField{Name: "newsletter", Kind: "bool"},
+		SchemaField{Name: "code", Kind: "string", Rules: "format=regexp:^[A-Z]+$ max_length=4", Required: true},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := []map[string]interface{}{
+		{"email": "bob@example.com", "code": "AB", "age": 30, "score": 9.5, "newsletter": true},
+		{"email": "bob@example.com", "code": "AB", "age": uint64(18), "score": json.Number("0"), "extra": []int{1}},
+		{"email": "bob@example.com", "code": "AB", "age": nil},
+	}
+	for i, record := range valid {
+		if ok, errs := schema.Validate(record); !ok {
+			t.Errorf("Record %d: expected to be valid, got %v", i, errs)
+		}
+	}
+
+	ok, errs := schema.Validate(map[string]interface{}{
+		"email":      "bob",
+		"age":        300,
+		"score":      float64(math.MaxFloat64),
+		"newsletter": "yes",
+		"code":       nil,
+	})
+	expected := []ValidationError{
+		{Key: "code", Message: "is required"},
+		{Key: "newsletter", Message: "must be of type bool"},
+		{Key: "score", Message: "must be of type float32"},
+		{Key: "age", Message: "must be of type uint8"},
+		{Key: "email", Message: "does not match email format"},
+	}
+	if ok || !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("Expected %v, got %v", expected, errs)
+	}
+
+	ok, errs = schema.Validate(map[string]interface{}{"email": "bob@example.com", "code": "ab", "age": 17.0, "score": -1})
+	expected = []ValidationError{
+		{Key: "code", Message: "does not match regexp format"},
+		{Key: "score", Message: "must be greater than or equal to 0.000000E+00"},
+		{Key: "age", Message: "must be greater than or equal to 18"},
+	}
+	if ok || len(errs) != len(expected) || errs[0] != expected[0] || errs[2] != expected[2] || errs[1].Key != "score" {
+		t.Fatalf("Expected %v, got %v", expected, errs)
+	}
+
+	if ok, errs := schema.Validate("record"); ok || errs[0].Key != "object" {
+		t.Fatal("Expected a non-record to be invalid", errs)
+	}
+}
+
+func TestNewSchemaFloat32(t *testing.T) {
+	schema, err := NewSchema(SchemaField{Name: "w", Kind: "float32", Rules: "min=0.1 max=0.3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []interface{}{0.1, json.Number("0.3"), float32(0.1)} {
+		if ok, errs := schema.Validate(map[string]interface{}{"w": w}); !ok {
+			t.Errorf("%v: expected the limit to be valid, got %v", w, errs)
+		}
+	}
+	for _, w := range []interface{}{0.09999999, json.Number("0.3000001")} {
+		if ok, _ := schema.Validate(map[string]interface{}{"w": w}); ok {
+			t.Errorf("%v: expected a value beyond the limit to be invalid", w)
+		}
+	}
+}
+
+func TestNewSchemaErrors(t *testing.T) {
+	invalid := map[string][]SchemaField{
+		"no name":            {{Kind: "string"}},
+		"duplicate name":     {{Name: "a", Kind: "string"}, {Name: "a", Kind: "int"}},
+		"unknown kind":       {{Name: "a", Kind: "decimal"}},
+		"malformed rules":    {{Name: "a", Kind: "int", Rules: "min"}},
+		"unknown validation": {{Name: "a", Kind: "string", Rules: "phone=us"}},
+		"out of range":       {{Name: "a", Kind: "uint8", Rules: "max=256"}},
+		"conflicting rules":  {{Name: "a", Kind: "int", Rules: "min=10 max=5"}},
+	}
+	for name, fields := range invalid {
+		if _, err := NewSchema(fields...); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestParseSchema(t *testing.T) {
+	vm := NewMap(&DefaultMap)
+	vm.AddResolver("plan", NewMemoryResolver("free", "pro"))
+	schema, err := vm.ParseSchema([]byte(`[
+		{"name": "plan", "kind": "string", "rules": "lookup=plan", "required": true},
+		{"name": "seats", "kind": "int", "rules": "min=1 max=50"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, errs := schema.Validate(map[string]interface{}{"plan": "enterprise", "seats": 0})
+	if ok || len(errs) != 2 || errs[0].Key != "seats" || errs[1].Key != "plan" || !strings.Contains(errs[1].Message, "plan") {
+		t.Fatal("Expected seats and plan errors", errs)
+	}
+
+	for _, src := range []string{`{}`, `[{"name": "a", "kind": "int", "rule": "min=1"}]`, `[{"name": "a", "kind": "int", "rules": "min=x"}]`} {
+		if _, err := vm.ParseSchema([]byte(src)); err == nil {
+			t.Errorf("%s: expected an error", src)
+		}
+	}
+}
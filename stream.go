@@ -0,0 +1,435 @@
+package validation
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ValidateJSON validates the JSON document read from reader using
+// DefaultValidationMap. See Map.ValidateJSONWithOptions.
+func ValidateJSON(reader io.Reader, objectType reflect.Type) (bool, []ValidationError, error) {
+	return DefaultMap.ValidateJSONWithOptions(reader, objectType, Options{})
+}
+
+// ValidateJSONWithOptions validates the JSON document read from reader using
+// DefaultValidationMap. See Map.ValidateJSONWithOptions.
+func ValidateJSONWithOptions(reader io.Reader, objectType reflect.Type, opts Options) (bool, []ValidationError, error) {
+	return DefaultMap.ValidateJSONWithOptions(reader, objectType, opts)
+}
+
+// ValidateJSON validates the JSON document read from reader as if it was
+// decoded into a value of objectType. See ValidateJSONWithOptions.
+func (vm *Map) ValidateJSON(reader io.Reader, objectType reflect.Type) (bool, []ValidationError, error) {
+	return vm.ValidateJSONWithOptions(reader, objectType, Options{})
+}
+
+// ValidateJSONWithOptions validates the JSON document read from reader as if
+// it was decoded with encoding/json into a value of objectType, a struct
+// type or a slice or array of structs, and validated by IsValidWithOptions
+// with opts.Nested set, without holding the whole document in memory.
+// Objects and arrays holding structs are read token by token; other values
+// are decoded one field at a time.
+//
+// Errors are keyed by the JSON Pointer (RFC 6901) of the invalid value, e.g.
+// "/items/12/price", with "" for the document itself. Pointers use the JSON
+// names of the fields, whatever the case of the properties in the document.
+// Values of the wrong type are invalid too. The errors of an object follow
+// those of the objects nested in it. Fields without a JSON name are
+// validated with their zero value and keyed by their Go name; validations
+// are passed the decoded fields of the object they belong to as obj, except
+// for nested objects.
+//
+// The returned error reports documents which are not valid JSON, failures
+// to read them and invalid validation tags, as a *CompileError. Reading
+// stops at the first error, or once opts tell validation to stop.
+func (vm *Map) ValidateJSONWithOptions(reader io.Reader, objectType reflect.Type, opts Options) (bool, []ValidationError, error) {
+	if objectType == nil || !streams(objectType) {
+		return false, nil, errors.New("validation: ValidateJSON requires a struct type or a slice or array of structs")
+	}
+	opts.Nested = true
+	s := &streamer{
+		run:     newRun(vm, opts),
+		decoder: json.NewDecoder(reader),
+		types:   map[reflect.Type]*streamType{},
+	}
+	goOn, err := s.value(objectType, "")
+	if err == nil && goOn {
+		if _, err = s.decoder.Token(); err == io.EOF {
+			err = nil
+			s.resolve()
+		} else if err == nil {
+			err = errors.New("validation: unexpected data after the JSON document")
+		}
+	}
+	if err != nil {
+		return false, s.errors, err
+	}
+	return len(s.errors) == 0, s.errors, nil
+}
+
+// streamer validates a JSON document while reading it.
+type streamer struct {
+	*run
+	decoder *json.Decoder
+	types   map[reflect.Type]*streamType
+}
+
+// streamType holds the fields of a struct type as found in JSON documents.
+type streamType struct {
+	compiled *compiledType
+	fields   []jsonField
+	names    map[string]int
+
+	// streamed reports for every field whether its values are read token
+	// by token, which are those holding structs without rules of their own.
+	streamed []bool
+
+	// ruleFields and nestedFields hold the field of every rule and nested
+	// field of compiled, or -1 if it has no JSON name, and ruleKeys and
+	// nestedKeys their escaped JSON Pointer tokens.
+	ruleFields, nestedFields []int
+	ruleKeys, nestedKeys     []string
+}
+
+// streamType returns the streamType of the struct type objectType.
+func (s *streamer) streamType(objectType reflect.Type) (*streamType, error) {
+	if st, ok := s.types[objectType]; ok {
+		return st, nil
+	}
+	compiled, err := s.vm.load(objectType)
+	if err != nil {
+		return nil, err
+	}
+	st := &streamType{
+		compiled: compiled,
+		fields:   jsonFields(objectType),
+		names:    map[string]int{},
+	}
+	fieldOf := func(index []int) (int, string) {
+		for i, field := range st.fields {
+			if reflect.DeepEqual(field.Index, index) {
+				return i, escapePointer(field.name)
+			}
+		}
+		return -1, escapePointer(objectType.FieldByIndex(index).Name)
+	}
+	for i, field := range st.fields {
+		st.names[field.name] = i
+		st.streamed = append(st.streamed, streams(field.Type))
+	}
+	for _, rule := range compiled.rules {
+		field, key := fieldOf(rule.index)
+		if field >= 0 {
+			st.streamed[field] = false
+		}
+		st.ruleFields = append(st.ruleFields, field)
+		st.ruleKeys = append(st.ruleKeys, key)
+	}
+	for _, nested := range compiled.nested {
+		field, key := fieldOf(nested.index)
+		st.nestedFields = append(st.nestedFields, field)
+		st.nestedKeys = append(st.nestedKeys, key)
+	}
+	s.types[objectType] = st
+	return st, nil
+}
+
+// field returns the field a property named name is decoded into, preferring
+// an exact match of its name like encoding/json.
+func (st *streamType) field(name string) (int, bool) {
+	if i, ok := st.names[name]; ok {
+		return i, true
+	}
+	for i, field := range st.fields {
+		if strings.EqualFold(field.name, name) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// streams reports whether values of typ are read token by token, i.e. typ
+// is a struct or a pointer, slice or array holding structs, which are not
+// decoded by encoding.TextUnmarshaler or json.Unmarshaler.
+func streams(typ reflect.Type) bool {
+	for {
+		if unmarshals(typ) {
+			return false
+		}
+		switch typ.Kind() {
+		case reflect.Struct:
+			return true
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			if typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 {
+				return false
+			}
+			typ = typ.Elem()
+		default:
+			return false
+		}
+	}
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// unmarshals reports whether values of typ decode themselves.
+func unmarshals(typ reflect.Type) bool {
+	ptr := reflect.PtrTo(typ)
+	return ptr.Implements(jsonUnmarshalerType) || ptr.Implements(textUnmarshalerType)
+}
+
+// value reads a value of the streamed type typ, found at pointer, and
+// reports whether validation should go on.
+func (s *streamer) value(typ reflect.Type, pointer string) (bool, error) {
+	if s.cancelled() {
+		return false, nil
+	}
+	token, err := s.decoder.Token()
+	if err != nil {
+		return false, err
+	}
+	elem := typ
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	switch {
+	case token == nil:
+		// null leaves values as they are, so structs keep their zero value.
+		if typ.Kind() == reflect.Struct {
+			return s.decoded(reflect.Zero(typ), pointer)
+		}
+		return true, nil
+	case elem.Kind() == reflect.Struct && token == json.Delim('{'):
+		return s.object(elem, pointer)
+	case elem.Kind() != reflect.Struct && token == json.Delim('['):
+		for i := 0; s.decoder.More(); i++ {
+			if goOn, err := s.value(elem.Elem(), pointer+"/"+strconv.Itoa(i)); err != nil || !goOn {
+				return goOn, err
+			}
+		}
+		_, err := s.decoder.Token()
+		return err == nil, err
+	}
+	if err := s.skip(token); err != nil {
+		return false, err
+	}
+	return s.fail(ValidationError{Key: pointer, Message: "must be of type " + typ.String()}), nil
+}
+
+// object reads the properties of an object decoded into the struct type
+// objectType, found at pointer, after its opening brace, and validates it.
+func (s *streamer) object(objectType reflect.Type, pointer string) (bool, error) {
+	st, err := s.streamType(objectType)
+	if err != nil {
+		return false, err
+	}
+	object := reflect.New(objectType).Elem()
+	// done marks the fields validated while streaming and those whose
+	// values are invalid, which are not validated again.
+	done := make([]bool, len(st.fields))
+	for s.decoder.More() {
+		token, err := s.decoder.Token()
+		if err != nil {
+			return false, err
+		}
+		name := token.(string)
+		i, ok := st.field(name)
+		if !ok {
+			if err := s.skipValue(); err != nil {
+				return false, err
+			}
+			continue
+		}
+		field := st.fields[i]
+		fieldPointer := pointer + "/" + escapePointer(field.name)
+		if st.streamed[i] {
+			done[i] = true
+			if goOn, err := s.value(field.Type, fieldPointer); err != nil || !goOn {
+				return goOn, err
+			}
+			continue
+		}
+		value, ok, err := s.decode(field.Type, fieldPointer)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			if s.full() {
+				return false, nil
+			}
+			done[i] = true
+			continue
+		}
+		settableField(object, field.Index).Set(value)
+	}
+	if _, err := s.decoder.Token(); err != nil {
+		return false, err
+	}
+	return s.validateObject(st, object, pointer, done)
+}
+
+// decode decodes the next value into a value of typ, found at pointer. ok
+// is false if the value is invalid, which is recorded as an error.
+func (s *streamer) decode(typ reflect.Type, pointer string) (value reflect.Value, ok bool, err error) {
+	var raw json.RawMessage
+	if err := s.decoder.Decode(&raw); err != nil {
+		return value, false, err
+	}
+	ptr := reflect.New(typ)
+	if err := json.Unmarshal(raw, ptr.Interface()); err != nil {
+		message := "is not a valid " + typ.String()
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			message = "must be of type " + typeErr.Type.String()
+		}
+		s.fail(ValidationError{Key: pointer, Message: message})
+		return value, false, nil
+	}
+	return ptr.Elem(), true, nil
+}
+
+// skipValue skips the next value.
+func (s *streamer) skipValue() error {
+	token, err := s.decoder.Token()
+	if err != nil {
+		return err
+	}
+	return s.skip(token)
+}
+
+// skip skips the rest of the value starting with token.
+func (s *streamer) skip(token json.Token) error {
+	depth := 0
+	for {
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+		var err error
+		if token, err = s.decoder.Token(); err != nil {
+			return err
+		}
+	}
+}
+
+// settableField returns the field at index in object, allocating the
+// embedded structs it is promoted through.
+func settableField(object reflect.Value, index []int) reflect.Value {
+	for _, i := range index[:len(index)-1] {
+		object = object.Field(i)
+		if object.Kind() == reflect.Ptr {
+			if object.IsNil() {
+				object.Set(reflect.New(object.Type().Elem()))
+			}
+			object = object.Elem()
+		}
+	}
+	return object.Field(index[len(index)-1])
+}
+
+// decoded validates value, decoded from the JSON value at pointer, like
+// validateValue validates the values below the top level.
+func (s *streamer) decoded(value reflect.Value, pointer string) (bool, error) {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return true, nil
+		}
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		st, err := s.streamType(value.Type())
+		if err != nil {
+			return false, err
+		}
+		return s.validateObject(st, value, pointer, nil)
+	case reflect.Slice, reflect.Array:
+		if !holdsStructs(value.Type().Elem()) {
+			return true, nil
+		}
+		for i := 0; i < value.Len(); i++ {
+			if goOn, err := s.decoded(value.Index(i), pointer+"/"+strconv.Itoa(i)); err != nil || !goOn {
+				return goOn, err
+			}
+		}
+	}
+	return true, nil
+}
+
+// validateObject runs the rules of st against object, found at pointer, and
+// validates its nested structs, skipping the fields marked in done.
+func (s *streamer) validateObject(st *streamType, object reflect.Value, pointer string, done []bool) (bool, error) {
+	isDone := func(field int) bool {
+		return field >= 0 && done != nil && done[field]
+	}
+
+	failedField := -1
+	for i, rule := range st.compiled.rules {
+		if s.cancelled() {
+			return false, nil
+		}
+		if rule.field == failedField || isDone(st.ruleFields[i]) {
+			continue
+		}
+		parent, ok := fieldParent(object, rule.index)
+		if !ok {
+			continue
+		}
+		field := parent.Field(rule.index[len(rule.index)-1])
+		key := pointer + "/" + st.ruleKeys[i]
+		if lv, ok := rule.Interface.(*lookupValidation); ok {
+			if kind := field.Kind(); kind == reflect.Slice || kind == reflect.Array {
+				for j := 0; j < field.Len(); j++ {
+					s.addLookup(lv.resolver, key+"/"+strconv.Itoa(j), fieldValue(field.Index(j)))
+				}
+			} else {
+				s.addLookup(lv.resolver, key, fieldValue(field))
+			}
+			continue
+		}
+		value := fieldValue(field)
+		var err *ValidationError
+		if cv, ok := rule.Interface.(ContextValidator); ok {
+			err = cv.ValidateContext(s.ctx, value, parent)
+		} else {
+			err = rule.Validate(value, parent)
+		}
+		if err != nil {
+			err.Key = key
+			if !s.fail(*err) {
+				return false, nil
+			}
+			if s.opts.Bail {
+				failedField = rule.field
+			}
+		}
+	}
+
+	for i, nested := range st.compiled.nested {
+		if isDone(st.nestedFields[i]) {
+			continue
+		}
+		parent, ok := fieldParent(object, nested.index)
+		if !ok {
+			continue
+		}
+		field := parent.Field(nested.index[len(nested.index)-1])
+		if goOn, err := s.decoded(field, pointer+"/"+st.nestedKeys[i]); err != nil || !goOn {
+			return goOn, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,156 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type streamTestItem struct {
+	SKU      string  `json:"sku" validation:"lookup=sku"`
+	Price    float64 `json:"price" validation:"min=0.01"`
+	Quantity int     `json:"qty,omitempty" validation:"min=1 max=100"`
+}
+
+type streamTestOrder struct {
+	ID       string `json:"id" validation:"min_length=3"`
+	Items    []streamTestItem
+	Billing  streamTestAddress  `json:"billing"`
+	Shipping *streamTestAddress `json:"shipping"`
+	Placed   time.Time          `json:"placed"`
+	Notes    string             `json:"-" validation:"max_length=2"`
+}
+
+type streamTestAddress struct {
+	Street string `json:"a/b" validation:"min_length=2"`
+}
+
+func streamTestMap() *Map {
+	vm := NewMap(&DefaultMap)
+	vm.AddResolver("sku", NewMemoryResolver("A1", "B2"))
+	return vm
+}
+
+func TestValidateJSON(t *testing.T) {
+	vm := streamTestMap()
+	orderType := reflect.TypeOf(streamTestOrder{})
+
+	ok, errs, err := vm.ValidateJSON(strings.NewReader(`{
+		"id": "ORD-1",
+		"unknown": {"nested": [1, 2, {"deep": null}]},
+		"items": [
+			{"sku": "A1", "price": 9.5, "qty": 2},
+			{"sku": "B2", "price": 0.5, "qty": 100}
+		],
+		"billing": {"a/b": "Main St"},
+		"shipping": null,
+		"placed": "2026-10-19T12:00:00Z"
+	}`), orderType)
+	if err != nil || !ok {
+		t.Fatal("Expected the order to be valid", errs, err)
+	}
+
+	ok, errs, err = vm.ValidateJSON(strings.NewReader(`{
+		"id": "O1",
+		"items": [
+			{"sku": "A1", "price": 9.5, "qty": 2},
+			{"sku": "C3", "price": 0, "qty": 101},
+			{"sku": "B2", "price": "free"},
+			null
+		],
+		"shipping": {"a/b": 1},
+		"PLACED": "yesterday"
+	}`), orderType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ValidationError{
+		{Key: "/Items/1/qty", Message: "must be less than or equal to 100"},
+		{Key: "/Items/1/price", Message: "must be greater than or equal to 1E-02"},
+		{Key: "/Items/2/price", Message: "must be of type float64"},
+		{Key: "/Items/2/qty", Message: "must be greater than or equal to 1"},
+		{Key: "/Items/3/qty", Message: "must be greater than or equal to 1"},
+		{Key: "/Items/3/price", Message: "must be greater than or equal to 1E-02"},
+		{Key: "/shipping/a~1b", Message: "must be of type string"},
+		{Key: "/placed", Message: "is not a valid time.Time"},
+		{Key: "/id", Message: "must be at least 3 characters"},
+		{Key: "/billing/a~1b", Message: "must be at least 2 characters"},
+		{Key: "/Items/1/sku", Message: "is not a valid sku"},
+		{Key: "/Items/3/sku", Message: "is not a valid sku"},
+	}
+	if ok || !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("Expected %v, got %v", expected, errs)
+	}
+}
+
+func TestValidateJSONTopLevel(t *testing.T) {
+	vm := streamTestMap()
+	ok, errs, err := vm.ValidateJSON(strings.NewReader(`[{"sku": "A1", "price": 1, "qty": 1}, {"sku": "A1", "price": 1}]`), reflect.TypeOf([]*streamTestItem{}))
+	expected := []ValidationError{{Key: "/1/qty", Message: "must be greater than or equal to 1"}}
+	if err != nil || ok || !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("Expected %v, got %v (%v)", expected, errs, err)
+	}
+
+	ok, errs, err = vm.ValidateJSON(strings.NewReader(`"order"`), reflect.TypeOf(streamTestOrder{}))
+	expected = []ValidationError{{Key: "", Message: "must be of type validation.streamTestOrder"}}
+	if err != nil || ok || !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("Expected %v, got %v (%v)", expected, errs, err)
+	}
+
+	if _, _, err := vm.ValidateJSON(strings.NewReader(`{}`), reflect.TypeOf("")); err == nil {
+		t.Fatal("Expected an error for a string type")
+	}
+}
+
+func TestValidateJSONOptions(t *testing.T) {
+	vm := streamTestMap()
+	document := `[{"sku": "A1", "price": 0, "qty": 0}, {"sku": "A1", "price": 0, "qty": 0}, {`
+	ok, errs, err := vm.ValidateJSONWithOptions(strings.NewReader(document), reflect.TypeOf([]streamTestItem{}), Options{FailFast: true})
+	expected := []ValidationError{{Key: "/0/qty", Message: "must be greater than or equal to 1"}}
+	if err != nil || ok || !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("Expected %v, got %v (%v)", expected, errs, err)
+	}
+
+	ok, errs, err = vm.ValidateJSONWithOptions(strings.NewReader(document), reflect.TypeOf([]streamTestItem{}), Options{MaxErrors: 3})
+	if err != nil || ok || len(errs) != 3 || errs[2].Key != "/1/qty" {
+		t.Fatal("Expected three errors", errs, err)
+	}
+}
+
+func TestValidateJSONErrors(t *testing.T) {
+	vm := streamTestMap()
+	itemsType := reflect.TypeOf([]streamTestItem{})
+	for _, document := range []string{`[{"sku": "A1", "price": 1, "qty": 1}`, `[{"sku" "A1"}]`, `[] []`, `[{"price": 1, "qty": 1}] x`} {
+		if _, _, err := vm.ValidateJSON(strings.NewReader(document), itemsType); err == nil {
+			t.Errorf("%s: expected an error", document)
+		}
+	}
+
+	type invalidTag struct {
+		Name string `validation:"min_length=x"`
+	}
+	_, _, err := vm.ValidateJSON(strings.NewReader(`{}`), reflect.TypeOf(invalidTag{}))
+	var compileErr *CompileError
+	if !errors.As(err, &compileErr) || compileErr.Field != "Name" {
+		t.Fatal("Expected a compile error", err)
+	}
+}
+
+type streamTestProbeBase struct {
+	ID int `validation:"min=1"`
+}
+
+type streamTestProbe struct {
+	*streamTestProbeBase
+	Name string `validation:"min_length=2"`
+}
+
+func TestValidateJSONUnexportedEmbedded(t *testing.T) {
+	ok, errs, err := streamTestMap().ValidateJSON(strings.NewReader(`{"ID": 0, "Name": "x"}`), reflect.TypeOf(streamTestProbe{}))
+	expected := []ValidationError{{Key: "/Name", Message: "must be at least 2 characters"}}
+	if err != nil || ok || !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("Expected %v, got %v (%v)", expected, errs, err)
+	}
+}
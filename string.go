@@ -53,6 +53,15 @@ func (v *maxLengthValidation) Validate(value interface{}, obj reflect.Value) *Va
 	return nil
 }
 
+func (v *maxLengthValidation) limit() (string, interface{}, bool) {
+	return "length", v.length, false
+}
+
+// Parameters returns the limit as length.
+func (v *maxLengthValidation) Parameters() map[string]interface{} {
+	return map[string]interface{}{"length": v.length}
+}
+
 func newMinLengthValidation(options string, kind reflect.Kind) (Interface, error) {
 	length, err := strconv.ParseInt(options, 10, 0)
 	if err != nil {
@@ -83,8 +92,21 @@ func (v *minLengthValidation) Validate(value interface{}, obj reflect.Value) *Va
 	return nil
 }
 
+func (v *minLengthValidation) limit() (string, interface{}, bool) {
+	return "length", v.length, true
+}
+
+// Parameters returns the limit as length.
+func (v *minLengthValidation) Parameters() map[string]interface{} {
+	return map[string]interface{}{"length": v.length}
+}
+
 //var emailRexep = regexp.MustCompile(`(?i)^[a-z0-9\._%+\-]+@[a-z0-9\.\-]+\.[a-z]{2,}$`)
-var emailRexep = regexp.MustCompile(`(?i)^[a-z0-9._%+\-]+@(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z0-9](?:[a-z0-9-]*[a-z0-9])?`)
+
+// EmailPattern is the regular expression used by format=email.
+const EmailPattern = `(?i)^[a-z0-9._%+\-]+@(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z0-9](?:[a-z0-9-]*[a-z0-9])?`
+
+var emailRexep = regexp.MustCompile(EmailPattern)
 
 func newFormatValidation(options string, kind reflect.Kind) (Interface, error) {
 	if strings.ToLower(options) == "email" {
@@ -128,6 +150,16 @@ func (v *formatValidation) Validate(value interface{}, obj reflect.Value) *Valid
 	return nil
 }
 
+// Parameters returns the regular expression as pattern and, for named
+// formats such as email, the name as format.
+func (v *formatValidation) Parameters() map[string]interface{} {
+	params := map[string]interface{}{"pattern": v.pattern.String()}
+	if v.patternName != "regexp" {
+		params["format"] = v.patternName
+	}
+	return params
+}
+
 func init() {
 	AddValidation("max_length", newMaxLengthValidation)
 	AddValidation("min_length", newMinLengthValidation)
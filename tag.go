@@ -0,0 +1,29 @@
+package validation
+
+import "strings"
+
+// Rule is a single validation of a validation tag, e.g. min_length=3 is the
+// rule named min_length with options 3.
+type Rule struct {
+	Name    string
+	Options string
+}
+
+// String returns the rule as it is written in a validation tag.
+func (r Rule) String() string {
+	return r.Name + "=" + r.Options
+}
+
+// ParseTag splits a validation tag into its rules. Rules are separated by a
+// single space and each must have the form name=options.
+func ParseTag(tag string) ([]Rule, error) {
+	var rules []Rule
+	for _, v := range strings.Split(tag, " ") {
+		comps := strings.Split(v, "=")
+		if len(comps) != 2 {
+			return nil, &ValidationError{Key: v, Message: "is not of the form name=options"}
+		}
+		rules = append(rules, Rule{Name: comps[0], Options: comps[1]})
+	}
+	return rules, nil
+}
@@ -0,0 +1,30 @@
+package validation
+
+import "testing"
+
+func TestParseTag(t *testing.T) {
+	rules, err := ParseTag("min_length=3 format=regexp:^[A-Z]+$")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 2 {
+		t.Fatal("Expected 2 rules", rules)
+	}
+	if rules[0] != (Rule{Name: "min_length", Options: "3"}) {
+		t.Fatal("Unexpected first rule", rules[0])
+	}
+	if rules[1] != (Rule{Name: "format", Options: "regexp:^[A-Z]+$"}) {
+		t.Fatal("Unexpected second rule", rules[1])
+	}
+	if rules[0].String() != "min_length=3" {
+		t.Fatal("Unexpected rule string", rules[0].String())
+	}
+}
+
+func TestParseTagInvalid(t *testing.T) {
+	for _, tag := range []string{"min", "min=1  max=2", "a=b=c"} {
+		if _, err := ParseTag(tag); err == nil {
+			t.Fatalf("Expected tag %q to be invalid", tag)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// Typed validates values of the struct type T. The validations of T are
+// resolved once when the Typed is created, so validating does not need to
+// look them up by type. They are resolved again after the validations they
+// use were registered anew or the type was dropped from the cache of the Map.
+type Typed[T any] struct {
+	vm       *Map
+	typ      reflect.Type
+	compiled atomic.Pointer[compiledType]
+}
+
+// For returns a Typed validating T using DefaultValidationMap. It panics if T
+// is not a struct type.
+func For[T any]() *Typed[T] {
+	return ForMap[T](&DefaultMap)
+}
+
+// ForMap returns a Typed validating T using vm. It panics if T is not a
+// struct type.
+func ForMap[T any](vm *Map) *Typed[T] {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		panic("validation: For requires a struct type, got " + typ.String())
+	}
+	tv := &Typed[T]{vm: vm, typ: typ}
+	tv.compiled.Store(vm.validations(typ))
+	return tv
+}
+
+// Validate determines if value is valid based on the validation tags of T.
+func (tv *Typed[T]) Validate(value T) (bool, []ValidationError) {
+	return tv.ValidateWithOptions(value, Options{})
+}
+
+// ValidateWithOptions determines if value is valid based on the validation
+// tags of T, stopping early as requested by opts.
+func (tv *Typed[T]) ValidateWithOptions(value T, opts Options) (bool, []ValidationError) {
+	r := newRun(tv.vm, opts)
+	if tv.validate(r, reflect.ValueOf(&value).Elem()) {
+		r.resolve()
+	}
+	return len(r.errors) == 0, r.errors
+}
+
+// ValidateAll validates every element of values concurrently. See
+// Map.IsValidAll.
+func (tv *Typed[T]) ValidateAll(values []T, opts Options) ([]ElementErrors, error) {
+	sliceValue := reflect.ValueOf(values)
+	return validateAll(tv.vm, len(values), opts, func(r *run, i int) bool {
+		return tv.validate(r, sliceValue.Index(i))
+	})
+}
+
+// validate validates objectValue during r and reports whether validation
+// should go on.
+func (tv *Typed[T]) validate(r *run, objectValue reflect.Value) bool {
+	compiled := tv.compiled.Load()
+	if !tv.vm.fresh(compiled) {
+		compiled = tv.vm.validations(tv.typ)
+		tv.compiled.Store(compiled)
+	}
+	return r.validateStruct(objectValue, compiled, "")
+}
@@ -0,0 +1,59 @@
+package validation
+
+import "testing"
+
+type typedTestType struct {
+	Name  string `validation:"min_length=2"`
+	Count uint   `validation:"max=10"`
+}
+
+func TestTypedValidate(t *testing.T) {
+	v := For[typedTestType]()
+
+	ok, errs := v.Validate(typedTestType{Name: "ok", Count: 3})
+	if !ok {
+		t.Fatal("Expected valid object", errs)
+	}
+
+	ok, errs = v.Validate(typedTestType{Name: "x", Count: 11})
+	if ok || len(errs) != 2 {
+		t.Fatal("Expected 2 errors", errs)
+	}
+
+	ok, errs = v.ValidateWithOptions(typedTestType{Name: "x", Count: 11}, Options{FailFast: true})
+	if ok || len(errs) != 1 {
+		t.Fatal("Expected a single error", errs)
+	}
+}
+
+func TestTypedValidateAll(t *testing.T) {
+	values := []typedTestType{{Name: "ok"}, {Name: "x"}, {Name: "ok", Count: 20}}
+
+	errs, err := For[typedTestType]().ValidateAll(values, Options{Workers: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 2 || errs[0].Index != 1 || errs[1].Index != 2 {
+		t.Fatal("Expected errors for elements 1 and 2", errs)
+	}
+}
+
+func TestTypedCustomMap(t *testing.T) {
+	vm := Map{}
+	vm.AddValidation("min_length", newMinLengthValidation)
+	vm.AddValidation("max", newMaxValueValidation)
+
+	ok, errs := ForMap[typedTestType](&vm).Validate(typedTestType{Name: "x"})
+	if ok || len(errs) != 1 {
+		t.Fatal("Expected a single error", errs)
+	}
+}
+
+func TestForNonStruct(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected For to panic for a non struct type")
+		}
+	}()
+	For[int]()
+}
@@ -3,10 +3,12 @@
 package validation
 
 import (
+	"context"
 	"log"
 	"reflect"
-	"strings"
+	"strconv"
 	"sync"
+	"sync/atomic"
 )
 
 // Interface specifies the necessary methods a validation must
@@ -19,6 +21,14 @@ type Interface interface {
 	Validate(value interface{}, obj reflect.Value) *ValidationError
 }
 
+// ContextValidator can optionally be implemented by a validation that needs
+// the context passed to IsValidContext, e.g. to honor deadlines, read
+// request-scoped values or call out to another service. When implemented,
+// ValidateContext is used instead of Validate.
+type ContextValidator interface {
+	ValidateContext(ctx context.Context, value interface{}, obj reflect.Value) *ValidationError
+}
+
 // Validation is an implementation of a Interface and can be used to
 // provide basic functionality to a new validation type through an anonymous
 // field
@@ -65,19 +75,69 @@ var DefaultMap = Map{}
 // when two Set happen at the same time,
 // latest that started wins.
 type Map struct {
-	validator               sync.Map // map[reflect.Type][]Interface
-	validationNameToBuilder sync.Map // map[string]func(string, reflect.Kind) (Interface, error)
+	// version is incremented whenever a validation is registered, so cached
+	// validations only need to be checked against the registrations they
+	// were built with after a change.
+	version                 atomic.Uint64
+	parent                  *Map
+	validator               sync.Map // map[reflect.Type]*compiledType
+	validationNameToBuilder sync.Map // map[string]*registration
+	resolvers               sync.Map // map[string]Resolver
+	types                   sync.Map // map[string]reflect.Type
+
+	// compiling holds the types being compiled, so concurrent first uses
+	// of a type wait for a single compilation.
+	compiling sync.Map // map[reflect.Type]*compileCall
+
+	// cache tracks the cached types in insertion order to bound their
+	// number.
+	cache typeCache
+
+	// rules holds the rules set with SetRules, replaced as a whole under
+	// rulesMu on every change.
+	rules   atomic.Pointer[ruleSet]
+	rulesMu sync.Mutex
 }
 
-func (vm *Map) get(k reflect.Type) []Interface {
-	v, ok := vm.validator.Load(k)
-	if !ok {
-		return []Interface{}
-	}
-	return v.([]Interface)
+// registration is a builder registered with AddValidation. Every call to
+// AddValidation creates a new registration, which tells the validations
+// built by different calls apart.
+type registration struct {
+	build func(string, reflect.Kind) (Interface, error)
 }
-func (vm *Map) set(k reflect.Type, v []Interface) {
-	vm.validator.Store(k, v)
+
+// NewMap creates a Map inheriting the validations and resolvers of parent,
+// which may be nil. Validations and resolvers registered on the new Map
+// override those of parent without affecting it, while those later added to
+// parent become visible to the new Map as well. Each Map caches the
+// validations of the types it validated on its own.
+func NewMap(parent *Map) *Map {
+	return &Map{parent: parent}
+}
+
+// Clone creates a Map with the same parent and a copy of the validations,
+// resolvers, types and rules registered on vm. Later registrations on either
+// Map do not affect the other.
+func (vm *Map) Clone() *Map {
+	clone := NewMap(vm.parent)
+	vm.validationNameToBuilder.Range(func(key, value interface{}) bool {
+		clone.validationNameToBuilder.Store(key, value)
+		return true
+	})
+	vm.resolvers.Range(func(key, value interface{}) bool {
+		clone.resolvers.Store(key, value)
+		return true
+	})
+	vm.types.Range(func(key, value interface{}) bool {
+		clone.types.Store(key, value)
+		return true
+	})
+	// Rule sets are replaced rather than changed, so the clone may share
+	// the current one.
+	vm.rulesMu.Lock()
+	clone.rules.Store(vm.rules.Load())
+	vm.rulesMu.Unlock()
+	return clone
 }
 
 // AddValidation registers the validation specified by key to the known
@@ -90,9 +150,51 @@ func AddValidation(key string, fn func(string, reflect.Kind) (Interface, error))
 
 // AddValidation registers the validation specified by key to the known
 // validations. If more than one validation registers with the same key, the
-// last one will become the validation for that key.
+// last one will become the validation for that key. Registering a nil fn
+// hides the validation a parent Map registered under key. Cached validations
+// of types using key are built again on next use.
 func (vm *Map) AddValidation(key string, fn func(string, reflect.Kind) (Interface, error)) {
-	vm.validationNameToBuilder.Store(key, fn)
+	vm.validationNameToBuilder.Store(key, &registration{build: fn})
+	vm.version.Add(1)
+}
+
+// HasValidation reports whether a validation is registered under name.
+func (vm *Map) HasValidation(name string) bool {
+	return vm.builder(name) != nil
+}
+
+// Build creates the validation for rule on a field of the given kind using
+// the builder registered under the name of the rule.
+func (vm *Map) Build(rule Rule, kind reflect.Kind) (Interface, error) {
+	fn := vm.builder(rule.Name)
+	if fn == nil {
+		return nil, unknownValidationError(rule.Name)
+	}
+	return fn(rule.Options, kind)
+}
+
+// builder returns the builder registered under name on vm or, failing that,
+// on the closest of its parents.
+func (vm *Map) builder(name string) func(string, reflect.Kind) (Interface, error) {
+	if reg := vm.registration(name); reg != nil {
+		return reg.build
+	}
+	return nil
+}
+
+// registration returns the registration of name on vm or, failing that, on
+// the closest of its parents.
+func (vm *Map) registration(name string) *registration {
+	for m := vm; m != nil; m = m.parent {
+		if reg, ok := m.validationNameToBuilder.Load(name); ok {
+			return reg.(*registration)
+		}
+	}
+	return nil
+}
+
+func unknownValidationError(name string) error {
+	return &ValidationError{Key: name, Message: "is not a known validation"}
 }
 
 // IsValid determines if an object is valid based on its validation tags
@@ -102,52 +204,239 @@ func IsValid(object interface{}) (bool, []ValidationError) {
 }
 
 // IsValid determines if an object is valid based on its validation tags.
+// Pointers and slice elements are validated with the validations of vm as
+// well; structs held by fields are not, see Options.Nested.
 func (vm *Map) IsValid(object interface{}) (bool, []ValidationError) {
-	objectValue := reflect.ValueOf(object)
-	objectType := reflect.TypeOf(object)
-	validations := vm.get(objectType)
-	if objectValue.Kind() == reflect.Ptr && !objectValue.IsNil() {
-		return IsValid(objectValue.Elem().Interface())
-	}
-	if len(validations) == 0 {
-		var err error
-		for i := objectType.NumField() - 1; i >= 0; i-- {
-			field := objectType.Field(i)
-			validationTag := field.Tag.Get("validation")
-			if len(validationTag) > 0 {
-				validationComps := strings.Split(validationTag, " ")
-				for _, v := range validationComps {
-					comps := strings.Split(v, "=")
-					if len(comps) != 2 {
-						log.Fatalln("Invalid Validation Specification:", objectType.Name(), field.Name, v)
-					}
-					var validation Interface
-					if builder, ok := vm.validationNameToBuilder.Load(comps[0]); ok && builder != nil {
-						fn := builder.(func(string, reflect.Kind) (Interface, error))
-						validation, err = fn(comps[1], field.Type.Kind())
-					} else {
-						log.Fatalln("Unknown validation named", comps[0])
-					}
-					if err != nil {
-						log.Fatalln("Error Creating Validation", objectType.Name(), field.Name, v, err)
-					}
-					validation.SetFieldName(field.Name)
-					validation.SetFieldIndex(i)
-					validations = append(validations, validation)
-				}
+	return vm.IsValidContext(context.Background(), object)
+}
+
+// IsValidContext determines if an object is valid based on its validation
+// tags using DefaultValidationMap. See Map.IsValidContext.
+func IsValidContext(ctx context.Context, object interface{}) (bool, []ValidationError) {
+	return DefaultMap.IsValidContext(ctx, object)
+}
+
+// IsValidContext determines if an object is valid based on its validation
+// tags. ctx is passed to every validation implementing ContextValidator. If
+// ctx is done before all validations have run, validation stops and the
+// returned errors end with one keyed by "context" describing ctx.Err().
+func (vm *Map) IsValidContext(ctx context.Context, object interface{}) (bool, []ValidationError) {
+	return vm.IsValidWithOptions(object, Options{Context: ctx})
+}
+
+// run holds the state of validating a single object.
+type run struct {
+	vm     *Map
+	ctx    context.Context
+	done   <-chan struct{}
+	opts   Options
+	errors []ValidationError
+
+	// lookups holds the pending lookups by resolver name, which are resolved
+	// in one batch per resolver once every field has been visited.
+	lookups       map[string][]lookup
+	resolverNames []string
+
+	// elements collects the errors of the lookups by element instead of
+	// errors when the lookups of several elements are resolved together.
+	elements [][]ValidationError
+
+	// validating holds the pointers being validated, to detect cycles.
+	validating map[visit]bool
+}
+
+// visit is a pointer followed during a run.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+func newRun(vm *Map, opts Options) *run {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &run{
+		vm:   vm,
+		ctx:  ctx,
+		done: ctx.Done(),
+		opts: opts,
+	}
+}
+
+// cancelled reports whether the context of the run is done, recording the
+// context error if it is.
+func (r *run) cancelled() bool {
+	select {
+	case <-r.done:
+		r.errors = append(r.errors, contextError(r.ctx))
+		return true
+	default:
+		return false
+	}
+}
+
+// fail records err and reports whether validation should go on.
+func (r *run) fail(err ValidationError) bool {
+	r.errors = append(r.errors, err)
+	return !r.full()
+}
+
+// full reports whether the run has found as many errors as it may report.
+func (r *run) full() bool {
+	if r.opts.FailFast {
+		return len(r.errors) > 0
+	}
+	return r.opts.MaxErrors > 0 && len(r.errors) >= r.opts.MaxErrors
+}
+
+// validateValue validates value, which may be a struct, a slice or array of
+// structs, or a pointer or interface holding one of these, and reports
+// whether validation should go on. Errors are keyed relative to path. At the
+// top level nil values and unsupported kinds are errors; below it, e.g. for
+// a nil pointer field, they are skipped.
+func (r *run) validateValue(value reflect.Value, path string, top bool) bool {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			break
+		}
+		if value.Kind() == reflect.Ptr {
+			if !r.enter(value) {
+				return true
 			}
+			defer r.leave(value)
 		}
-		vm.set(objectType, validations)
+		value = value.Elem()
 	}
 
-	var errors []ValidationError
-	for _, validation := range validations {
-		field := objectValue.Field(validation.FieldIndex())
-		value := field.Interface()
-		if err := validation.Validate(value, objectValue); err != nil {
-			errors = append(errors, *err)
+	switch value.Kind() {
+	case reflect.Struct:
+		return r.validateStruct(value, r.vm.validations(value.Type()), path)
+	case reflect.Slice, reflect.Array:
+		if top && !holdsStructs(value.Type().Elem()) {
+			break
 		}
+		for i := 0; i < value.Len(); i++ {
+			if !r.validateValue(value.Index(i), path+"["+strconv.Itoa(i)+"]", top) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if !top {
+		return true
+	}
+	if !value.IsValid() || value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		return r.fail(ValidationError{Key: objectKey(path), Message: "is nil"})
+	}
+	return r.fail(ValidationError{Key: objectKey(path), Message: "of type " + value.Type().String() + " cannot be validated"})
+}
+
+// enter records that the value ptr points to is being validated. It returns
+// false if it already is, i.e. ptr is part of a cycle of pointers.
+func (r *run) enter(ptr reflect.Value) bool {
+	if r.validating == nil {
+		r.validating = map[visit]bool{}
+	}
+	v := visit{ptr.Pointer(), ptr.Type()}
+	if r.validating[v] {
+		return false
 	}
+	r.validating[v] = true
+	return true
+}
+
+// leave records that the value ptr points to has been validated.
+func (r *run) leave(ptr reflect.Value) {
+	delete(r.validating, visit{ptr.Pointer(), ptr.Type()})
+}
 
-	return len(errors) == 0, errors
+// validateStruct runs the rules of the type of objectValue against it and
+// validates its nested structs if r.opts.Nested is set. It returns false if
+// the run was cancelled or stopped because of its options.
+func (r *run) validateStruct(objectValue reflect.Value, compiled *compiledType, path string) bool {
+	failedField := -1
+	for _, rule := range compiled.rules {
+		if r.cancelled() {
+			return false
+		}
+		if rule.field == failedField {
+			continue
+		}
+		parent, ok := fieldParent(objectValue, rule.index)
+		if !ok {
+			continue
+		}
+		field := parent.Field(rule.index[len(rule.index)-1])
+		if lv, ok := rule.Interface.(*lookupValidation); ok {
+			r.addLookups(lv, field, path)
+			continue
+		}
+		value := fieldValue(field)
+		var err *ValidationError
+		if cv, ok := rule.Interface.(ContextValidator); ok {
+			err = cv.ValidateContext(r.ctx, value, parent)
+		} else {
+			err = rule.Validate(value, parent)
+		}
+		if err != nil {
+			err.Key = joinKey(path, err.Key)
+			if !r.fail(*err) {
+				return false
+			}
+			if r.opts.Bail {
+				failedField = rule.field
+			}
+		}
+	}
+
+	if !r.opts.Nested {
+		return true
+	}
+	for _, nested := range compiled.nested {
+		parent, ok := fieldParent(objectValue, nested.index)
+		if !ok {
+			continue
+		}
+		field := parent.Field(nested.index[len(nested.index)-1])
+		if !r.validateValue(field, joinKey(path, nested.name), false) {
+			return false
+		}
+	}
+	return true
+}
+
+// validations returns the compiled validations for objectType, building and
+// caching them from the validation tags on first use.
+func (vm *Map) validations(objectType reflect.Type) *compiledType {
+	compiled, err := vm.load(objectType)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return compiled
+}
+
+// joinKey prefixes key with the path of the struct it belongs to.
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// objectKey is the key of errors about the validated value itself.
+func objectKey(path string) string {
+	if path == "" {
+		return "object"
+	}
+	return path
+}
+
+// contextError describes why ctx is done as a ValidationError.
+func contextError(ctx context.Context) ValidationError {
+	return ValidationError{
+		Key:     "context",
+		Message: ctx.Err().Error(),
+	}
 }
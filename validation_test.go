@@ -1,14 +1,16 @@
 package validation
 
 import (
+	"context"
 	"reflect"
+	"strings"
 	"sync"
 	"testing"
 )
 
 func TestValidationMap_Atomicity(t *testing.T) {
 	vm := Map{}
-	typ := reflect.TypeOf(vm)
+	typ := reflect.TypeOf(&vm)
 	wg1 := sync.WaitGroup{}
 	wg1.Add(1)
 	wg2 := sync.WaitGroup{}
@@ -31,3 +33,410 @@ func TestValidationMap_Atomicity(t *testing.T) {
 	wg1.Done() // start !
 	wg2.Wait()
 }
+
+type tenantKey struct{}
+
+type tenantValidation struct {
+	Validation
+}
+
+func (v *tenantValidation) ValidateContext(ctx context.Context, value interface{}, obj reflect.Value) *ValidationError {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	if value.(string) != tenant {
+		return &ValidationError{
+			Key:     v.FieldName(),
+			Message: "must match the request tenant",
+		}
+	}
+	return nil
+}
+
+func newTenantValidation(options string, kind reflect.Kind) (Interface, error) {
+	return &tenantValidation{}, nil
+}
+
+func TestIsValidContext(t *testing.T) {
+	type order struct {
+		Tenant string `validation:"tenant=true"`
+		Name   string `validation:"min_length=1"`
+	}
+	vm := Map{}
+	vm.AddValidation("tenant", newTenantValidation)
+	vm.AddValidation("min_length", newMinLengthValidation)
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	obj := order{Tenant: "acme", Name: "widget"}
+
+	ok, errs := vm.IsValidContext(ctx, obj)
+	if !ok {
+		t.Fatal("Tenant matches the context and should be valid", errs)
+	}
+
+	obj.Tenant = "other"
+	ok, errs = vm.IsValidContext(ctx, obj)
+	if ok || len(errs) != 1 || errs[0].Key != "Tenant" {
+		t.Fatal("Expected a single Tenant error", errs)
+	}
+}
+
+func TestIsValidContextCancelled(t *testing.T) {
+	type order struct {
+		Name string `validation:"min_length=1"`
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ok, errs := IsValidContext(ctx, order{})
+	if ok {
+		t.Fatal("Cancelled context should not be valid")
+	}
+	if len(errs) != 1 || errs[0].Key != "context" || errs[0].Message != context.Canceled.Error() {
+		t.Fatal("Expected only the context error", errs)
+	}
+}
+
+func TestMapBuild(t *testing.T) {
+	vm := Map{}
+	vm.AddValidation("min", newMinValueValidation)
+
+	if !vm.HasValidation("min") || vm.HasValidation("max") {
+		t.Fatal("Expected only min to be registered")
+	}
+	if _, err := vm.Build(Rule{Name: "min", Options: "3"}, reflect.Int); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vm.Build(Rule{Name: "min", Options: "3"}, reflect.String); err == nil {
+		t.Fatal("Expected min on a string to fail")
+	}
+	if _, err := vm.Build(Rule{Name: "max", Options: "3"}, reflect.Int); err == nil {
+		t.Fatal("Expected unknown validation to fail")
+	}
+}
+
+type kindsTestItem struct {
+	Price int `validation:"min=1"`
+}
+
+type kindsTestOrder struct {
+	Name     string `validation:"min_length=1"`
+	Items    []kindsTestItem
+	Primary  *kindsTestItem
+	Extra    interface{}
+	Shipping struct {
+		Street string `validation:"min_length=2"`
+	}
+	Next *kindsTestOrder
+}
+
+func TestIsValidNilAndUnsupported(t *testing.T) {
+	var nilOrder *kindsTestOrder
+	var nilInterface interface{} = nilOrder
+	inputs := map[string]interface{}{
+		"nil":           nil,
+		"nil pointer":   nilOrder,
+		"nil interface": &nilInterface,
+	}
+	for name, input := range inputs {
+		ok, errs := IsValid(input)
+		if ok || len(errs) != 1 || errs[0].Key != "object" || errs[0].Message != "is nil" {
+			t.Errorf("%s: expected an is nil error, got %v", name, errs)
+		}
+	}
+
+	unsupported := map[string]interface{}{
+		"int":          42,
+		"map":          map[string]int{},
+		"int slice":    []int{1},
+		"func":         func() {},
+		"int pointer":  new(int),
+		"string array": [1]string{},
+	}
+	for name, input := range unsupported {
+		ok, errs := IsValid(input)
+		if ok || len(errs) != 1 || errs[0].Key != "object" || !strings.HasSuffix(errs[0].Message, "cannot be validated") {
+			t.Errorf("%s: expected a cannot be validated error, got %v", name, errs)
+		}
+	}
+}
+
+func TestIsValidPointersAndInterfaces(t *testing.T) {
+	order := &kindsTestOrder{Shipping: struct {
+		Street string `validation:"min_length=2"`
+	}{"Main"}}
+	ptr := &order
+	var iface interface{} = ptr
+
+	for name, input := range map[string]interface{}{"pointer": order, "double pointer": ptr, "interface": &iface} {
+		ok, errs := IsValid(input)
+		if ok || len(errs) != 1 || errs[0].Key != "Name" {
+			t.Errorf("%s: expected a Name error, got %v", name, errs)
+		}
+	}
+}
+
+func TestIsValidSlices(t *testing.T) {
+	items := []kindsTestItem{{Price: 1}, {Price: 0}, {Price: 2}, {Price: -1}}
+	ok, errs := IsValid(items)
+	if ok || len(errs) != 2 || errs[0].Key != "[1].Price" || errs[1].Key != "[3].Price" {
+		t.Fatal("Expected errors for elements 1 and 3", errs)
+	}
+
+	ok, errs = IsValid([]*kindsTestItem{{Price: 1}, nil})
+	if ok || len(errs) != 1 || errs[0].Key != "[1]" || errs[0].Message != "is nil" {
+		t.Fatal("Expected an is nil error for element 1", errs)
+	}
+
+	ok, errs = IsValid([]kindsTestItem{})
+	if !ok {
+		t.Fatal("Expected an empty slice to be valid", errs)
+	}
+}
+
+func TestIsValidNested(t *testing.T) {
+	order := kindsTestOrder{
+		Name:    "order",
+		Items:   []kindsTestItem{{Price: 1}, {Price: 0}},
+		Primary: &kindsTestItem{Price: 0},
+		Extra:   kindsTestItem{Price: -1},
+	}
+	order.Next = &order
+
+	if ok, errs := IsValid(&order); !ok {
+		t.Fatal("Expected nested structs to be skipped without Options.Nested", errs)
+	}
+	ok, errs := IsValidWithOptions(&order, Options{Nested: true})
+	if ok || len(errs) != 4 {
+		t.Fatal("Expected 4 errors", errs)
+	}
+	keys := []string{"Shipping.Street", "Extra.Price", "Primary.Price", "Items[1].Price"}
+	for i, key := range keys {
+		if errs[i].Key != key {
+			t.Fatalf("Error %d: expected key %s, got %v", i, key, errs)
+		}
+	}
+}
+
+type rejectValidation struct {
+	Validation
+}
+
+func (v *rejectValidation) Validate(value interface{}, obj reflect.Value) *ValidationError {
+	return &ValidationError{Key: v.FieldName(), Message: "rejected"}
+}
+
+func TestMapIsolation(t *testing.T) {
+	vm := Map{}
+	vm.AddValidation("min", func(options string, kind reflect.Kind) (Interface, error) {
+		return &rejectValidation{}, nil
+	})
+	vm.AddValidation("min_length", func(options string, kind reflect.Kind) (Interface, error) {
+		return &rejectValidation{}, nil
+	})
+
+	order := &kindsTestOrder{
+		Name:    "order",
+		Items:   []kindsTestItem{{Price: 1}},
+		Primary: &kindsTestItem{Price: 1},
+		Shipping: struct {
+			Street string `validation:"min_length=2"`
+		}{"Main"},
+	}
+	if ok, errs := IsValidWithOptions(order, Options{Nested: true}); !ok {
+		t.Fatal("Expected the order to be valid with DefaultMap", errs)
+	}
+
+	inputs := map[string]interface{}{"pointer": order, "double pointer": &order, "value": *order}
+	for name, input := range inputs {
+		ok, errs := vm.IsValidWithOptions(input, Options{Nested: true})
+		if ok || len(errs) != 4 {
+			t.Fatalf("%s: expected 4 errors of the private map, got %v", name, errs)
+		}
+		for _, err := range errs {
+			if err.Message != "rejected" {
+				t.Fatalf("%s: expected only errors of the private map, got %v", name, errs)
+			}
+		}
+	}
+
+	ok, errs := vm.IsValid([]*kindsTestItem{{Price: 1}})
+	if ok || len(errs) != 1 || errs[0].Key != "[0].Price" || errs[0].Message != "rejected" {
+		t.Fatal("Expected the element to be validated by the private map", errs)
+	}
+	results, err := vm.IsValidAll([]kindsTestItem{{Price: 1}}, Options{})
+	if err != nil || len(results) != 1 || results[0].Errors[0].Message != "rejected" {
+		t.Fatal("Expected IsValidAll to use the private map", results, err)
+	}
+	if ok, errs := ForMap[kindsTestItem](&vm).Validate(kindsTestItem{Price: 1}); ok || errs[0].Message != "rejected" {
+		t.Fatal("Expected Typed to use the private map", errs)
+	}
+
+	if ok, errs := IsValid(order); !ok {
+		t.Fatal("Expected DefaultMap to be unaffected by the private map", errs)
+	}
+}
+
+func TestNewMap(t *testing.T) {
+	tenant := NewMap(&DefaultMap)
+	tenant.AddValidation("min", func(options string, kind reflect.Kind) (Interface, error) {
+		return &rejectValidation{}, nil
+	})
+
+	item := kindsTestItem{Price: 1}
+	ok, errs := tenant.IsValid(item)
+	if ok || len(errs) != 1 || errs[0].Message != "rejected" {
+		t.Fatal("Expected the tenant override of min", errs)
+	}
+	if ok, errs := IsValid(item); !ok {
+		t.Fatal("Expected the override not to leak into the parent", errs)
+	}
+	if !tenant.HasValidation("min_length") || !tenant.HasValidation("lookup") {
+		t.Fatal("Expected the validations of the parent to be inherited")
+	}
+
+	parent := NewMap(nil)
+	child := NewMap(parent)
+	if child.HasValidation("min") {
+		t.Fatal("Expected a map without parent to start empty")
+	}
+	parent.AddValidation("min", newMinValueValidation)
+	if !child.HasValidation("min") {
+		t.Fatal("Expected later validations of the parent to be inherited")
+	}
+	child.AddValidation("min", nil)
+	if child.HasValidation("min") || !parent.HasValidation("min") {
+		t.Fatal("Expected a nil validation to hide the one of the parent only")
+	}
+}
+
+func TestMapClone(t *testing.T) {
+	vm := NewMap(nil)
+	vm.AddValidation("min", newMinValueValidation)
+	vm.AddResolver("sku", NewMemoryResolver("A1"))
+
+	clone := vm.Clone()
+	clone.AddValidation("min", func(options string, kind reflect.Kind) (Interface, error) {
+		return &rejectValidation{}, nil
+	})
+	vm.AddValidation("max", newMaxValueValidation)
+
+	if _, ok := clone.resolver("sku"); !ok {
+		t.Fatal("Expected the resolvers to be copied")
+	}
+	if clone.HasValidation("max") {
+		t.Fatal("Expected later validations of the original not to be copied")
+	}
+	if ok, errs := vm.IsValid(kindsTestItem{Price: 1}); !ok {
+		t.Fatal("Expected the original to be unaffected by the clone", errs)
+	}
+	if ok, errs := clone.IsValid(kindsTestItem{Price: 1}); ok || errs[0].Message != "rejected" {
+		t.Fatal("Expected the clone to use its own validation", errs)
+	}
+}
+
+func TestMapCloneRules(t *testing.T) {
+	vm := ruleFileTestMap()
+	if err := vm.LoadRules([]byte(`{"Customer": {"Name": "max_length=3"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	customer := ruleSetTestType{rulesTestBase: rulesTestBase{ID: 1}, Name: "abcdef", Email: "a@example.com"}
+
+	clone := vm.Clone()
+	if ok, errs := clone.IsValid(customer); ok || len(errs) != 1 || errs[0].Key != "Name" {
+		t.Fatal("Expected the rules to be copied", errs)
+	}
+	if err := clone.LoadRules([]byte(`{"Customer": {"Name": "max_length=10"}}`)); err != nil {
+		t.Fatal("Expected the registered types to be copied", err)
+	}
+	if ok, errs := clone.IsValid(customer); !ok {
+		t.Fatal("Expected the clone to use its new rules", errs)
+	}
+	if ok, errs := vm.IsValid(customer); ok || len(errs) != 1 {
+		t.Fatal("Expected the original to keep its rules", errs)
+	}
+}
+
+func TestAddValidationInvalidatesCache(t *testing.T) {
+	parent := NewMap(nil)
+	parent.AddValidation("min", newMinValueValidation)
+	child := NewMap(parent)
+	typed := ForMap[kindsTestItem](child)
+	item := kindsTestItem{Price: 1}
+
+	if ok, errs := child.IsValid(item); !ok {
+		t.Fatal("Expected the item to be valid", errs)
+	}
+	parent.AddValidation("min", func(options string, kind reflect.Kind) (Interface, error) {
+		return &rejectValidation{}, nil
+	})
+	if ok, errs := child.IsValid(item); ok || errs[0].Message != "rejected" {
+		t.Fatal("Expected the new validation of the parent to be used", errs)
+	}
+	if ok, errs := typed.Validate(item); ok || errs[0].Message != "rejected" {
+		t.Fatal("Expected Typed to use the new validation", errs)
+	}
+
+	compiled := child.get(reflect.TypeOf(item))
+	child.AddValidation("max", newMaxValueValidation)
+	if child.get(reflect.TypeOf(item)) != compiled {
+		t.Fatal("Expected validations of types not using max to stay cached")
+	}
+}
+
+func TestMapResetAndForget(t *testing.T) {
+	vm := NewMap(&DefaultMap)
+	itemType := reflect.TypeOf(kindsTestItem{})
+	orderType := reflect.TypeOf(kindsTestOrder{})
+	if err := vm.Compile(itemType); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.Compile(orderType); err != nil {
+		t.Fatal(err)
+	}
+	typed := ForMap[kindsTestItem](vm)
+
+	compiled := vm.get(itemType)
+	vm.Forget(itemType)
+	if vm.get(itemType) != nil || vm.get(orderType) == nil {
+		t.Fatal("Expected only the forgotten type to be dropped")
+	}
+	typed.Validate(kindsTestItem{Price: 1})
+	if vm.get(itemType) == nil || vm.get(itemType) == compiled {
+		t.Fatal("Expected Typed to compile the forgotten type again")
+	}
+
+	vm.Reset()
+	if vm.get(itemType) != nil || vm.get(orderType) != nil {
+		t.Fatal("Expected every type to be dropped")
+	}
+}
+
+func TestAddValidationConcurrent(t *testing.T) {
+	vm := NewMap(nil)
+	vm.AddValidation("min", newMinValueValidation)
+	typed := ForMap[kindsTestItem](vm)
+	reject := func(options string, kind reflect.Kind) (Interface, error) {
+		return &rejectValidation{}, nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			vm.AddValidation("min", reject)
+			vm.AddValidation("min", newMinValueValidation)
+		}
+		vm.AddValidation("min", reject)
+	}()
+	for i := 0; i < 100; i++ {
+		vm.IsValid(kindsTestItem{Price: 1})
+		typed.Validate(kindsTestItem{Price: 1})
+	}
+	<-done
+
+	if ok, errs := vm.IsValid(kindsTestItem{Price: 1}); ok || errs[0].Message != "rejected" {
+		t.Fatal("Expected the last registered validation to be used", errs)
+	}
+	if ok, errs := typed.Validate(kindsTestItem{Price: 1}); ok || errs[0].Message != "rejected" {
+		t.Fatal("Expected Typed to use the last registered validation", errs)
+	}
+}
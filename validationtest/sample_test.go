@@ -0,0 +1,39 @@
+package validationtest
+
+//go:generate go run ../cmd/validationgen -output sample_validation_test.go sample_test.go
+
+type sample struct {
+	Base
+	*Audit
+	Name   string  `validation:"min_length=1 max_length=20"`
+	Email  string  `validation:"format=email"`
+	Code   string  `validation:"format=regexp:^[A-Za-z0-9]{0,3}$"`
+	Age    uint8   `validation:"min=18 max=200"`
+	Delta  int16   `validation:"min=-100 max=100"`
+	Score  float64 `validation:"min=-1.5 max=10"`
+	Weight float32 `validation:"max=99.5"`
+	Notes  string
+	Home   Address
+}
+
+// Base is embedded in sample; its Name is hidden by sample.Name.
+type Base struct {
+	ID   uint   `validation:"min=1"`
+	Name string `validation:"max_length=3"`
+}
+
+// Audit is embedded by pointer in sample.
+type Audit struct {
+	Author string `validation:"min_length=1 max_length=8"`
+}
+
+// Address is held by sample; its rules only apply with Options.Nested.
+type Address struct {
+	Street string `validation:"min_length=1"`
+}
+
+// secret has unexported fields, which testing/quick cannot fill.
+type secret struct {
+	code  string `validation:"min_length=4"`
+	count int8   `validation:"max=3"`
+}
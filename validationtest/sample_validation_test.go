@@ -0,0 +1,107 @@
+// Code generated by validationgen; DO NOT EDIT.
+
+package validationtest
+
+import (
+	"regexp"
+
+	validation "github.com/BakedSoftware/go-validation"
+)
+
+var (
+	validationgenPattern0 = regexp.MustCompile("^[A-Za-z0-9]{0,3}$")
+	validationgenPattern1 = regexp.MustCompile(validation.EmailPattern)
+)
+
+// Validate checks sample against its validation tags.
+func (v sample) Validate() validation.ValidationErrors {
+	var errs validation.ValidationErrors
+	if float64(v.Weight) > 99.5 {
+		errs = append(errs, validation.ValidationError{Key: "Weight", Message: "must be less than or equal to 9.95E+01"})
+	}
+	if float64(v.Score) < -1.5 {
+		errs = append(errs, validation.ValidationError{Key: "Score", Message: "must be greater than or equal to -1.5E+00"})
+	}
+	if float64(v.Score) > 10 {
+		errs = append(errs, validation.ValidationError{Key: "Score", Message: "must be less than or equal to 1E+01"})
+	}
+	if int64(v.Delta) < -100 {
+		errs = append(errs, validation.ValidationError{Key: "Delta", Message: "must be greater than or equal to -100"})
+	}
+	if int64(v.Delta) > 100 {
+		errs = append(errs, validation.ValidationError{Key: "Delta", Message: "must be less than or equal to 100"})
+	}
+	if uint64(v.Age) < 18 {
+		errs = append(errs, validation.ValidationError{Key: "Age", Message: "must be greater than or equal to 18"})
+	}
+	if uint64(v.Age) > 200 {
+		errs = append(errs, validation.ValidationError{Key: "Age", Message: "must be less than or equal to 200"})
+	}
+	if !validationgenPattern0.MatchString(v.Code) {
+		errs = append(errs, validation.ValidationError{Key: "Code", Message: "does not match regexp format"})
+	}
+	if !validationgenPattern1.MatchString(v.Email) {
+		errs = append(errs, validation.ValidationError{Key: "Email", Message: "does not match email format"})
+	}
+	if len(v.Name) < 1 {
+		errs = append(errs, validation.ValidationError{Key: "Name", Message: "must be at least 1 characters"})
+	}
+	if len(v.Name) > 20 {
+		errs = append(errs, validation.ValidationError{Key: "Name", Message: "must be no more than 20 characters"})
+	}
+	if v.Audit != nil && len(v.Audit.Author) < 1 {
+		errs = append(errs, validation.ValidationError{Key: "Author", Message: "must be at least 1 characters"})
+	}
+	if v.Audit != nil && len(v.Audit.Author) > 8 {
+		errs = append(errs, validation.ValidationError{Key: "Author", Message: "must be no more than 8 characters"})
+	}
+	if uint64(v.Base.ID) < 1 {
+		errs = append(errs, validation.ValidationError{Key: "ID", Message: "must be greater than or equal to 1"})
+	}
+	return errs
+}
+
+// Validate checks Base against its validation tags.
+func (v Base) Validate() validation.ValidationErrors {
+	var errs validation.ValidationErrors
+	if len(v.Name) > 3 {
+		errs = append(errs, validation.ValidationError{Key: "Name", Message: "must be no more than 3 characters"})
+	}
+	if uint64(v.ID) < 1 {
+		errs = append(errs, validation.ValidationError{Key: "ID", Message: "must be greater than or equal to 1"})
+	}
+	return errs
+}
+
+// Validate checks Audit against its validation tags.
+func (v Audit) Validate() validation.ValidationErrors {
+	var errs validation.ValidationErrors
+	if len(v.Author) < 1 {
+		errs = append(errs, validation.ValidationError{Key: "Author", Message: "must be at least 1 characters"})
+	}
+	if len(v.Author) > 8 {
+		errs = append(errs, validation.ValidationError{Key: "Author", Message: "must be no more than 8 characters"})
+	}
+	return errs
+}
+
+// Validate checks Address against its validation tags.
+func (v Address) Validate() validation.ValidationErrors {
+	var errs validation.ValidationErrors
+	if len(v.Street) < 1 {
+		errs = append(errs, validation.ValidationError{Key: "Street", Message: "must be at least 1 characters"})
+	}
+	return errs
+}
+
+// Validate checks secret against its validation tags.
+func (v secret) Validate() validation.ValidationErrors {
+	var errs validation.ValidationErrors
+	if int64(v.count) > 3 {
+		errs = append(errs, validation.ValidationError{Key: "count", Message: "must be less than or equal to 3"})
+	}
+	if len(v.code) < 4 {
+		errs = append(errs, validation.ValidationError{Key: "code", Message: "must be at least 4 characters"})
+	}
+	return errs
+}
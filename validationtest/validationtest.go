@@ -0,0 +1,115 @@
+// Package validationtest provides helpers to check that validators generated
+// by validationgen agree with the reflective validation of
+// github.com/BakedSoftware/go-validation.
+package validationtest
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+	"testing/quick"
+
+	validation "github.com/BakedSoftware/go-validation"
+)
+
+// Generated is implemented by the types validationgen generates a Validate
+// method for.
+type Generated interface {
+	Validate() validation.ValidationErrors
+}
+
+// Divergence describes a value for which Map.IsValid and the generated
+// Validate method disagree.
+type Divergence struct {
+	Value      interface{}
+	Reflective []validation.ValidationError
+	Generated  []validation.ValidationError
+}
+
+func (d *Divergence) Error() string {
+	return fmt.Sprintf("validationtest: %#v: Map.IsValid returned %v, Validate returned %v", d.Value, d.Reflective, d.Generated)
+}
+
+// Compare validates v with vm.IsValid and with its generated Validate method.
+// It returns a *Divergence if the two do not report the same errors in the
+// same order.
+func Compare(vm *validation.Map, v Generated) error {
+	_, reflective := vm.IsValid(v)
+	generated := v.Validate()
+	if len(reflective) == len(generated) {
+		equal := true
+		for i := range reflective {
+			if reflective[i] != generated[i] {
+				equal = false
+				break
+			}
+		}
+		if equal {
+			return nil
+		}
+	}
+	return &Divergence{
+		Value:      v,
+		Reflective: reflective,
+		Generated:  generated,
+	}
+}
+
+// CompareRandom compares the zero value of the type of sample, sample itself
+// and n values of that type generated randomly with testing/quick. It returns
+// the first divergence found.
+func CompareRandom(vm *validation.Map, sample Generated, n int, r *rand.Rand) error {
+	typ := reflect.TypeOf(sample)
+	if err := Compare(vm, reflect.Zero(typ).Interface().(Generated)); err != nil {
+		return err
+	}
+	if err := Compare(vm, sample); err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		value, ok := quick.Value(typ, r)
+		if !ok {
+			return fmt.Errorf("validationtest: cannot generate random values of type %s", typ)
+		}
+		if err := Compare(vm, value.Interface().(Generated)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SeedEnv names the environment variable Check takes a fixed seed from, to
+// reproduce the random values of a failure.
+const SeedEnv = "VALIDATIONTEST_SEED"
+
+// Check runs CompareRandom with n random values using DefaultValidationMap
+// and fails t on the first divergence, logging the seed of the values. The
+// seed is random unless SeedEnv is set.
+func Check(t testing.TB, sample Generated, n int) {
+	t.Helper()
+	seed, err := checkSeed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rand.New(rand.NewSource(seed))
+	if err := CompareRandom(&validation.DefaultMap, sample, n, r); err != nil {
+		t.Logf("random values seeded with %d, rerun with %s=%d", seed, SeedEnv, seed)
+		t.Fatal(err)
+	}
+}
+
+// checkSeed returns the seed set in SeedEnv or else a random one.
+func checkSeed() (int64, error) {
+	value := os.Getenv(SeedEnv)
+	if value == "" {
+		return rand.Int63(), nil
+	}
+	seed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("validationtest: invalid %s %q", SeedEnv, value)
+	}
+	return seed, nil
+}
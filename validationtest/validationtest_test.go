@@ -0,0 +1,111 @@
+package validationtest
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	validation "github.com/BakedSoftware/go-validation"
+)
+
+func TestGeneratedMatchesReflective(t *testing.T) {
+	Check(t, sample{Base: Base{ID: 1}, Name: "Jo", Email: "jo@example.com", Code: "A1", Age: 30}, 1000)
+}
+
+func TestCompareBoundaries(t *testing.T) {
+	values := []sample{
+		{Age: 17},
+		{Age: 18, Delta: -101, Score: -1.5},
+		{Age: 200, Delta: 100, Score: 10, Weight: 99.5},
+		{Age: 201, Delta: 101, Score: 10.01, Weight: 99.51},
+		{Name: "abcdefghijklmnopqrstu", Code: "ABCD", Email: "@example.com"},
+		{Base: Base{ID: 1, Name: "hidden"}, Audit: &Audit{}},
+		{Audit: &Audit{Author: "too long author"}},
+		{Home: Address{}},
+	}
+	for _, v := range values {
+		if err := Compare(&validation.DefaultMap, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCompareNested(t *testing.T) {
+	v := sample{Base: Base{ID: 1}, Name: "Jo", Email: "jo@example.com", Age: 30}
+	if err := Compare(&validation.DefaultMap, v); err != nil {
+		t.Fatal(err)
+	}
+	_, errs := validation.IsValidWithOptions(v, validation.Options{Nested: true})
+	if len(errs) != 1 || errs[0].Key != "Home.Street" {
+		t.Fatal("Expected Options.Nested to validate Home", errs)
+	}
+}
+
+func TestCompareUnexported(t *testing.T) {
+	for _, v := range []secret{{}, {code: "abcd", count: 3}, {code: "abc", count: 4}} {
+		if err := Compare(&validation.DefaultMap, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+type divergent struct {
+	Name string `validation:"min_length=10"`
+}
+
+func (v divergent) Validate() validation.ValidationErrors {
+	if len(v.Name) < 5 {
+		return validation.ValidationErrors{{Key: "Name", Message: "must be at least 10 characters"}}
+	}
+	return nil
+}
+
+func TestCompareDivergence(t *testing.T) {
+	err := Compare(&validation.DefaultMap, divergent{Name: "abcdef"})
+	d, ok := err.(*Divergence)
+	if !ok {
+		t.Fatal("Expected a divergence", err)
+	}
+	if len(d.Reflective) != 1 || len(d.Generated) != 0 {
+		t.Fatal("Unexpected divergence", d)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	if err := CompareRandom(&validation.DefaultMap, divergent{}, 100, r); err == nil {
+		t.Fatal("Expected random values to find the divergence")
+	}
+}
+
+// recorder records what Check logs instead of failing.
+type recorder struct {
+	testing.TB
+	logs   []string
+	failed bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Logf(format string, args ...interface{}) {
+	r.logs = append(r.logs, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatal(args ...interface{}) {
+	r.failed = true
+}
+
+func TestCheckSeed(t *testing.T) {
+	t.Setenv(SeedEnv, "42")
+	r := &recorder{}
+	Check(r, divergent{}, 100)
+	if !r.failed || len(r.logs) != 1 || !strings.Contains(r.logs[0], SeedEnv+"=42") {
+		t.Fatal("Expected the seed to be logged", r.logs)
+	}
+
+	t.Setenv(SeedEnv, "x")
+	r = &recorder{}
+	Check(r, sample{}, 1)
+	if !r.failed {
+		t.Fatal("Expected an invalid seed to fail")
+	}
+}